@@ -0,0 +1,292 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Arg is the specification of a positional argument of a [Command].
+type Arg struct {
+	// Name of the argument, used as placeholder in usage.
+	Name string
+	// Usage description of the argument.
+	Usage string
+	// Marks the argument as optional. Optional arguments must follow required ones.
+	Optional bool
+	// Marks the argument as variadic, consuming the remaining arguments.
+	// Only the last argument may be variadic.
+	Variadic bool
+	// Minimum and maximum number of values of a variadic argument.
+	// By default, a required variadic argument takes at least one value,
+	// and a zero maximum means no limit. An optional argument takes no minimum.
+	Min, Max int
+	// Value returns the [flag.Value] parsing the argument, retrievable with [GetArg]
+	// once the command runs. It is called on every run, much like [Command.Flags],
+	// and the value is set once per value of a variadic argument, like a flag set
+	// multiple times.
+	Value func() flag.Value
+}
+
+// placeholder returns the placeholder of the argument in usage.
+func (a Arg) placeholder() string {
+	s := a.Name
+	if a.Variadic {
+		s += "..."
+	}
+	if a.Optional {
+		s = "[" + s + "]"
+	}
+	return s
+}
+
+// ArgsError is returned by [Command.Run] when the positional arguments do not match [Command.Args].
+type ArgsError struct {
+	// Name of the first missing argument, empty if there are too many arguments.
+	Name string
+	// Minimum and maximum number of arguments expected, a negative maximum meaning no limit.
+	Min, Max int
+	// Number of arguments given.
+	Got int
+}
+
+func (e *ArgsError) Error() string {
+	if e.Got < e.Min {
+		return fmt.Sprintf("missing required argument %s", e.Name)
+	}
+	return fmt.Sprintf("too many arguments: expected at most %d, got %d", e.Max, e.Got)
+}
+
+// argsBounds returns the minimum and maximum number of arguments of the command,
+// a negative maximum meaning no limit.
+func (c *Command) argsBounds() (int, int) {
+	lo, hi := 0, 0
+	for _, a := range c.Args {
+		switch {
+		case a.Variadic:
+			lo += a.variadicMin()
+			if a.Max > 0 {
+				hi += a.Max
+			} else {
+				hi = -1
+			}
+		case !a.Optional:
+			lo++
+			hi++
+		default:
+			hi++
+		}
+	}
+	return lo, hi
+}
+
+func (a Arg) variadicMin() int {
+	switch {
+	case a.Optional:
+		return 0
+	case a.Min > 0:
+		return a.Min
+	default:
+		return 1
+	}
+}
+
+// checkArgs checks args against the specification of [Command.Args], if any.
+func (c *Command) checkArgs(args []string) error {
+	if c.Args == nil {
+		return nil
+	}
+
+	lo, hi := c.argsBounds()
+	switch {
+	case len(args) < lo:
+		name := c.Args[len(c.Args)-1].Name // the variadic argument lacks values
+		if len(args) < len(c.Args) {
+			name = c.Args[len(args)].Name
+		}
+		return &ArgsError{name, lo, hi, len(args)}
+	case hi >= 0 && len(args) > hi:
+		return &ArgsError{"", lo, hi, len(args)}
+	default:
+		return nil
+	}
+}
+
+// parseArgs checks args and parses them with new values of the [Arg.Value] of [Command.Args],
+// which are returned by name.
+func (c *Command) parseArgs(args []string) (map[string]flag.Value, error) {
+	if err := c.checkArgs(args); err != nil {
+		return nil, err
+	}
+
+	parsed := map[string]flag.Value{}
+	for i, a := range c.Args {
+		if a.Value == nil {
+			continue
+		}
+		value := a.Value()
+		parsed[a.Name] = value
+		if i >= len(args) {
+			continue
+		}
+
+		values := args[i : i+1]
+		if a.Variadic {
+			values = args[i:]
+		}
+		for _, s := range values {
+			if err := value.Set(s); err != nil {
+				return nil, fmt.Errorf("invalid value %q for argument %s: %w", s, a.Name, err)
+			}
+		}
+	}
+	return parsed, nil
+}
+
+// validateArgs checks the specification of [Command.Args].
+func (c *Command) validateArgs(path string) error {
+	errs := []error{}
+	optional := false
+	for i, a := range c.Args {
+		if a.Variadic && i != len(c.Args)-1 {
+			errs = append(errs, fmt.Errorf("variadic argument %s of command %q is not the last one", a.Name, path))
+		}
+		if !a.Optional && optional {
+			errs = append(errs, fmt.Errorf("required argument %s of command %q follows an optional one", a.Name, path))
+		}
+		if !a.Variadic && (a.Min != 0 || a.Max != 0) {
+			errs = append(errs, fmt.Errorf("argument %s of command %q sets Min or Max but is not variadic", a.Name, path))
+		}
+		if a.Variadic && a.Max > 0 && a.Max < a.Min {
+			errs = append(errs, fmt.Errorf("variadic argument %s of command %q has Max %d below Min %d",
+				a.Name, path, a.Max, a.Min))
+		}
+		if a.Optional && a.Min > 0 {
+			errs = append(errs, fmt.Errorf("optional argument %s of command %q sets Min %d", a.Name, path, a.Min))
+		}
+		optional = optional || a.Optional
+	}
+	return errors.Join(errs...)
+}
+
+// usageArgs returns [Command.UsageArgs], or the placeholders of [Command.Args] if empty.
+func (c *Command) usageArgs() string {
+	if c.UsageArgs != "" || c.Args == nil {
+		return c.UsageArgs
+	}
+	placeholders := []string{}
+	for _, a := range c.Args {
+		placeholders = append(placeholders, a.placeholder())
+	}
+	return strings.Join(placeholders, " ")
+}
+
+// printArgs prints the arguments listing of [Usage].
+func printArgs(w io.Writer, args []Arg) {
+	width := 0
+	for _, a := range args {
+		width = max(width, len(a.Name))
+	}
+	for _, a := range args {
+		fmt.Fprintf(w, "  %-*s    %s\n", width, a.Name, a.Usage)
+	}
+}
+
+// lookupArg returns the value of the named argument of the commands stored in the context,
+// or nil if there is none.
+func lookupArg(ctx context.Context, name string) flag.Value {
+	for s := scopeFrom(ctx); s != nil; s = s.parent {
+		if value, ok := s.args[name]; ok {
+			return value
+		}
+	}
+	return nil
+}
+
+// GetArg looks for the named argument and returns the value of its [Arg.Value].
+// It returns nil if:
+//   - the specified [Arg] was not found, or has no [flag.Value]
+//   - its [flag.Value] does not implement [flag.Getter]
+//   - the [flag.Getter] itself returns nil
+func GetArg(ctx context.Context, name string) any {
+	value := lookupArg(ctx, name)
+	if value == nil {
+		return nil
+	}
+
+	g, ok := value.(flag.Getter)
+	if !ok {
+		return nil
+	}
+
+	return g.Get()
+}
+
+// ArgNotFoundError is returned by [LookupArg] when the named argument was not found.
+type ArgNotFoundError struct {
+	Name string
+}
+
+func (e *ArgNotFoundError) Error() string {
+	return fmt.Sprintf("argument %s not found", e.Name)
+}
+
+// ArgNotGetterError is returned by [LookupArg] when the [flag.Value] does not implement [flag.Getter].
+type ArgNotGetterError struct {
+	Name string
+}
+
+func (e *ArgNotGetterError) Error() string {
+	return fmt.Sprintf("argument %s value does not implement flag.Getter", e.Name)
+}
+
+// ArgTypeError is returned by [LookupArg] when the argument value is not of the requested type.
+type ArgTypeError struct {
+	Name  string
+	Value any
+	Type  reflect.Type
+}
+
+func (e *ArgTypeError) Error() string {
+	return fmt.Sprintf("argument %s value is of type %T, not %v", e.Name, e.Value, e.Type)
+}
+
+// LookupArg looks for the named argument and returns the value of its [Arg.Value] as a T.
+// It returns an error if:
+//   - the specified [Arg] was not found, or has no [flag.Value], see [ArgNotFoundError]
+//   - its [flag.Value] does not implement [flag.Getter], see [ArgNotGetterError]
+//   - the value returned by the [flag.Getter] is not a T, see [ArgTypeError]
+func LookupArg[T any](ctx context.Context, name string) (T, error) {
+	var zero T
+
+	value := lookupArg(ctx, name)
+	if value == nil {
+		return zero, &ArgNotFoundError{name}
+	}
+
+	g, ok := value.(flag.Getter)
+	if !ok {
+		return zero, &ArgNotGetterError{name}
+	}
+
+	val := g.Get()
+	v, ok := val.(T)
+	if !ok {
+		return zero, &ArgTypeError{name, val, reflect.TypeFor[T]()}
+	}
+
+	return v, nil
+}
+
+// MustGetArg is like [LookupArg] but panics if an error occurs.
+func MustGetArg[T any](ctx context.Context, name string) T {
+	v, err := LookupArg[T](ctx, name)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
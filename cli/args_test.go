@@ -0,0 +1,241 @@
+package cli_test
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/cli"
+	"github.com/rlibaert/flag/values"
+)
+
+func ExampleArg() {
+	c := cli.Command{
+		Name:  "cp",
+		Usage: "Copy files",
+		Args: []cli.Arg{
+			{Name: "source", Usage: "file to copy"},
+			{Name: "destination", Usage: "file or directory to copy to", Optional: true},
+			{Name: "sources", Usage: "more files to copy", Optional: true, Variadic: true},
+		},
+	}
+
+	fs := flag.NewFlagSet("", flag.PanicOnError)
+	fs.SetOutput(os.Stdout)
+
+	cli.Usage(&c, fs)
+
+	// Output:
+	// Usage: cp source [destination] [sources...]
+	//
+	// Copy files
+	//
+	// Arguments:
+	//   source         file to copy
+	//   destination    file or directory to copy to
+	//   sources        more files to copy
+}
+
+func TestCommandRunArgs(t *testing.T) {
+	testCases := []struct {
+		name    string
+		spec    []cli.Arg
+		args    []string
+		message string
+	}{
+		{
+			name:    "no specification",
+			spec:    nil,
+			args:    []string{"foo", "bar"},
+			message: "command terminated: [foo bar]",
+		},
+		{
+			name:    "exact",
+			spec:    []cli.Arg{{Name: "src"}, {Name: "dst"}},
+			args:    []string{"foo", "bar"},
+			message: "command terminated: [foo bar]",
+		},
+		{
+			name:    "missing",
+			spec:    []cli.Arg{{Name: "src"}, {Name: "dst"}},
+			args:    []string{"foo"},
+			message: "missing required argument dst",
+		},
+		{
+			name:    "too many",
+			spec:    []cli.Arg{{Name: "src"}, {Name: "dst", Optional: true}},
+			args:    []string{"foo", "bar", "baz"},
+			message: "too many arguments: expected at most 2, got 3",
+		},
+		{
+			name:    "none expected",
+			spec:    []cli.Arg{},
+			args:    []string{"foo"},
+			message: "too many arguments: expected at most 0, got 1",
+		},
+		{
+			name:    "optional",
+			spec:    []cli.Arg{{Name: "src"}, {Name: "dst", Optional: true}},
+			args:    []string{"foo"},
+			message: "command terminated: [foo]",
+		},
+		{
+			name:    "variadic",
+			spec:    []cli.Arg{{Name: "src"}, {Name: "files", Variadic: true}},
+			args:    []string{"foo", "bar", "baz", "qux"},
+			message: "command terminated: [foo bar baz qux]",
+		},
+		{
+			name:    "variadic missing",
+			spec:    []cli.Arg{{Name: "src"}, {Name: "files", Variadic: true}},
+			args:    []string{"foo"},
+			message: "missing required argument files",
+		},
+		{
+			name:    "variadic optional",
+			spec:    []cli.Arg{{Name: "src"}, {Name: "files", Optional: true, Variadic: true}},
+			args:    []string{"foo"},
+			message: "command terminated: [foo]",
+		},
+		{
+			name:    "variadic min",
+			spec:    []cli.Arg{{Name: "files", Variadic: true, Min: 2, Max: 3}},
+			args:    []string{"foo"},
+			message: "missing required argument files",
+		},
+		{
+			name:    "variadic optional min",
+			spec:    []cli.Arg{{Name: "files", Optional: true, Variadic: true, Min: 2}},
+			args:    []string{},
+			message: "command terminated: []",
+		},
+		{
+			name:    "variadic max",
+			spec:    []cli.Arg{{Name: "src", Optional: true}, {Name: "files", Optional: true, Variadic: true, Max: 2}},
+			args:    []string{"foo", "bar", "baz", "qux"},
+			message: "too many arguments: expected at most 3, got 4",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := cli.Command{
+				Flags: func(fs *flag.FlagSet) { fs.SetOutput(io.Discard) },
+				Args:  tc.spec,
+				Func: func(_ context.Context, args []string) error {
+					return fmt.Errorf("command terminated: %v", args)
+				},
+			}
+			err := c.Run(context.Background(), tc.args)
+			require.EqualError(t, err, tc.message)
+		})
+	}
+
+	t.Run("error type", func(t *testing.T) {
+		c := cli.Command{
+			Flags: func(fs *flag.FlagSet) { fs.SetOutput(io.Discard) },
+			Args:  []cli.Arg{{Name: "src"}, {Name: "dst"}},
+			Func:  func(context.Context, []string) error { return nil },
+		}
+		var argsErr *cli.ArgsError
+		require.ErrorAs(t, c.Run(context.Background(), []string{"foo"}), &argsErr)
+		require.Equal(t, &cli.ArgsError{Name: "dst", Min: 2, Max: 2, Got: 1}, argsErr)
+	})
+}
+
+func TestValidateArgs(t *testing.T) {
+	c := cli.Command{
+		Name: "foo",
+		Args: []cli.Arg{
+			{Name: "files", Variadic: true},
+			{Name: "opt", Optional: true},
+			{Name: "req"},
+		},
+	}
+	require.EqualError(t, c.Validate(), "variadic argument files of command \"foo\" is not the last one\n"+
+		"required argument req of command \"foo\" follows an optional one")
+
+	c = cli.Command{
+		Name: "foo",
+		Args: []cli.Arg{
+			{Name: "src", Min: 1},
+			{Name: "dst", Max: 2},
+			{Name: "files", Optional: true, Variadic: true, Min: 3, Max: 2},
+		},
+	}
+	require.EqualError(t, c.Validate(), "argument src of command \"foo\" sets Min or Max but is not variadic\n"+
+		"argument dst of command \"foo\" sets Min or Max but is not variadic\n"+
+		"variadic argument files of command \"foo\" has Max 2 below Min 3\n"+
+		"optional argument files of command \"foo\" sets Min 3")
+}
+
+func TestCommandRunArgsValues(t *testing.T) {
+	c := func() *cli.Command {
+		return &cli.Command{
+			Flags: func(fs *flag.FlagSet) { fs.SetOutput(io.Discard) },
+			Args: []cli.Arg{
+				{Name: "url", Value: func() flag.Value { return values.Stringer(url.Parse) }},
+				{Name: "count", Value: func() flag.Value { return values.Basic[int]() }, Optional: true},
+				{Name: "tags", Value: func() flag.Value { return values.BasicList[string]() }, Optional: true, Variadic: true},
+			},
+			Func: func(ctx context.Context, _ []string) error {
+				_, err := cli.LookupArg[string](ctx, "url")
+				require.ErrorAs(t, err, new(*cli.ArgTypeError))
+				require.EqualError(t, err, "argument url value is of type *url.URL, not string")
+
+				_, err = cli.LookupArg[int](ctx, "foo")
+				require.ErrorAs(t, err, new(*cli.ArgNotFoundError))
+				require.EqualError(t, err, "argument foo not found")
+
+				return fmt.Errorf("command terminated: %v %v %v",
+					cli.MustGetArg[*url.URL](ctx, "url"),
+					cli.GetArg(ctx, "count"),
+					cli.GetArg(ctx, "tags"),
+				)
+			},
+		}
+	}
+
+	t.Run("parses values", func(t *testing.T) {
+		err := c().Run(context.Background(), []string{"http://example.com", "42", "foo", "bar"})
+		require.EqualError(t, err, "command terminated: http://example.com 42 [foo bar]")
+	})
+
+	t.Run("optional values", func(t *testing.T) {
+		err := c().Run(context.Background(), []string{"http://example.com"})
+		require.EqualError(t, err, "command terminated: http://example.com 0 []")
+	})
+
+	t.Run("fresh values on every run", func(t *testing.T) {
+		c := c()
+		err := c.Run(context.Background(), []string{"http://example.com", "42", "foo", "bar"})
+		require.EqualError(t, err, "command terminated: http://example.com 42 [foo bar]")
+		err = c.Run(context.Background(), []string{"http://example.org", "1", "baz"})
+		require.EqualError(t, err, "command terminated: http://example.org 1 [baz]")
+	})
+
+	t.Run("reports parsing errors", func(t *testing.T) {
+		err := c().Run(context.Background(), []string{"http://example.com", "notint"})
+		require.ErrorContains(t, err, `invalid value "notint" for argument count: strconv.ParseInt: parsing "notint": invalid syntax`)
+	})
+
+	t.Run("not getter", func(t *testing.T) {
+		c := cli.Command{
+			Args: []cli.Arg{{Name: "arg", Value: func() flag.Value { return new(colorValue) }}},
+			Func: func(ctx context.Context, _ []string) error {
+				require.Nil(t, cli.GetArg(ctx, "arg"))
+				_, err := cli.LookupArg[string](ctx, "arg")
+				require.ErrorAs(t, err, new(*cli.ArgNotGetterError))
+				require.EqualError(t, err, "argument arg value does not implement flag.Getter")
+				return nil
+			},
+		}
+		require.NoError(t, c.Run(context.Background(), []string{"red"}))
+	})
+}
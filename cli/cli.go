@@ -6,21 +6,55 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
+	"os"
+	"reflect"
 	"slices"
+	"strings"
 )
 
 // Command is the basic building block of command-line interfaces.
 type Command struct {
 	// Name of the command.
 	Name string
+	// Alternative names of the command.
+	Aliases []string
+	// Hides the command from usage and completions, while keeping it runnable.
+	Hidden bool
+	// Deprecation message printed when the command is run, marking it as deprecated.
+	Deprecated string
+	// Category heading under which the command is listed in the usage of its parent.
+	// Uncategorized commands are listed first, then categories follow in order of appearance.
+	Category string
 	// Usage description of the command.
 	Usage string
 	// Usage command argument placeholders.
+	// If empty, it is generated from [Command.Args].
 	UsageArgs string
+	// Positional arguments specification, checked before running [Command.Func].
+	Args []Arg
 	// Flags definition function for this command.
 	Flags func(fs *flag.FlagSet)
 	// Flags marked as required, enabling early failure.
+	// A flag is considered set when [flag.FlagSet.Visit] reports it, whether it was
+	// set on the command line or by any other source using [flag.FlagSet.Set], such as
+	// [github.com/rlibaert/flag/values.FlagSetEnvRegisterer] for environment variables.
+	// Flags left to their default value are not.
 	FlagsRequired []string
+	// Groups of flags of which at most one may be set.
+	// Like [Command.FlagsRequired], flag groups consider flags set by any source, but the flags
+	// of a group set on the command line override the ones set by other sources: only if none
+	// is set on the command line are the flags set by other sources, such as environment
+	// variables, checked against the group constraint.
+	FlagsMutuallyExclusive [][]string
+	// Groups of flags of which at least one must be set.
+	// Combined with [Command.FlagsMutuallyExclusive], exactly one must be set.
+	FlagsOneRequired [][]string
+	// Groups of flags which must be set together, or not at all.
+	FlagsRequiredTogether [][]string
+	// Groups of flags whose first flag, when set, requires the others to be set,
+	// e.g. -tls-cert requiring -tls-key while -tls-key may be set alone.
+	FlagsDependencies [][]string
 	// Function for adding custom code and passing values around the execution
 	// of the actual [Command]. Any error returned here is reported by the
 	// [Command.Run] method.
@@ -28,7 +62,7 @@ type Command struct {
 	// For instance, this can be useful for handling shared resources:
 	//
 	// func(parent context.Context, run func(ctx context.Context) error) error {
-	// 	db, err := sql.Open("postgres", cli.Get(parent, "dsn").(string))
+	// 	db, err := sql.Open("postgres", cli.MustGet[string](parent, "dsn"))
 	// 	if err != nil {
 	// 		return err
 	// 	}
@@ -42,6 +76,13 @@ type Command struct {
 	RunContext func(parent context.Context, run func(ctx context.Context) error) error
 	// Subcommands definitions.
 	Subcommands []*Command
+	// Enables matching subcommands by unambiguous prefixes of their names or aliases.
+	PrefixMatching bool
+	// Enables the automatic "help [COMMAND...]" subcommand, printing the usage of
+	// any command of the tree. It also makes help flags found anywhere in the
+	// arguments print the usage of the deepest matched command, unless they are
+	// the value of a flag or a flag defined by the command.
+	Help bool
 	// Command function to run.
 	Func func(ctx context.Context, args []string) error
 }
@@ -52,13 +93,10 @@ var Usage = func(c *Command, fs *flag.FlagSet) { //nolint: gochecknoglobals // m
 	w := fs.Output()
 
 	usage := []any{"Usage:", c.Name}
-	if c.Flags != nil {
-		usage = append(usage, "[options]")
-	}
-	if len(c.Subcommands) > 0 {
-		usage = append(usage, "COMMAND")
+	for _, placeholder := range c.placeholders() {
+		usage = append(usage, placeholder)
 	}
-	usage = append(usage, c.UsageArgs)
+	usage = append(usage, c.usageArgs())
 	fmt.Fprintln(w, usage...)
 
 	if c.Usage != "" {
@@ -69,22 +107,65 @@ var Usage = func(c *Command, fs *flag.FlagSet) { //nolint: gochecknoglobals // m
 	if c.Flags != nil {
 		fmt.Fprintln(w)
 		fmt.Fprintln(w, "Options:")
-		fs.PrintDefaults()
+		c.annotatedFlags(fs).PrintDefaults()
 	}
 
-	if len(c.Subcommands) > 0 {
+	if len(c.Args) > 0 {
 		fmt.Fprintln(w)
-		fmt.Fprintln(w, "Commands:")
-
-		lines := []fmt.Stringer{}
-		width := 0
-		for _, c := range c.Subcommands {
-			lines = append(lines,
-				stringerFunc(func() string { return fmt.Sprintf("  %-*s    %s", width, c.Name, c.Usage) }),
-			)
-			width = max(width, len(c.Name))
+		fmt.Fprintln(w, "Arguments:")
+		printArgs(w, c.Args)
+	}
+
+	printSubcommands(w, c.visibleSubcommands())
+}
+
+// placeholders returns the placeholders of the options and subcommands following the command name in usage.
+func (c *Command) placeholders() []string {
+	placeholders := []string{}
+	if c.Flags != nil {
+		placeholders = append(placeholders, "[options]")
+	}
+	if len(c.visibleSubcommands()) > 0 {
+		placeholders = append(placeholders, "COMMAND")
+	}
+	return placeholders
+}
+
+// synopsis returns the placeholders following the command name in generated documentation.
+func (c *Command) synopsis() []string {
+	synopsis := c.placeholders()
+	if usageArgs := c.usageArgs(); usageArgs != "" {
+		synopsis = append(synopsis, usageArgs)
+	}
+	return synopsis
+}
+
+// printSubcommands prints the subcommands listing of [Usage], grouped by category.
+func printSubcommands(w io.Writer, subcommands []*Command) {
+	categories := []string{""}
+	lines := map[string][]fmt.Stringer{}
+	width := 0
+	for _, c := range subcommands {
+		if !slices.Contains(categories, c.Category) {
+			categories = append(categories, c.Category)
+		}
+		name := strings.Join(append([]string{c.Name}, c.Aliases...), ", ")
+		lines[c.Category] = append(lines[c.Category],
+			stringerFunc(func() string { return fmt.Sprintf("  %-*s    %s", width, name, c.Usage) }),
+		)
+		width = max(width, len(name))
+	}
+	for _, category := range categories {
+		if len(lines[category]) == 0 {
+			continue
+		}
+		heading := category
+		if heading == "" {
+			heading = "Commands"
 		}
-		for _, line := range lines {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, heading+":")
+		for _, line := range lines[category] {
 			fmt.Fprintln(w, line)
 		}
 	}
@@ -97,18 +178,30 @@ func (f stringerFunc) String() string { return f() }
 // Run runs the command tree by parsing environment & flag arguments into [flag.Value] and store them in the context.
 // If a subcommand can be run using the remaining non-flag arguments, then it is run, otherwise it runs the [Command]'s function.
 // If there is no function to run, it prints usage and returns.
+//
+// When the first argument of the root command is the hidden "__complete" entrypoint,
+// it prints completion candidates for the remaining arguments instead, see [Command.Complete].
 func (c *Command) Run(ctx context.Context, args []string) error {
-	fs := flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	if len(args) > 0 && args[0] == completeArg && scopeFrom(ctx) == nil {
+		return c.Complete(os.Stdout, args[1:])
+	}
+	return c.run(ctx, args, false)
+}
+
+// run implements [Command.Run]. If help is set, the command tree is walked
+// down to the deepest matched command in order to print its usage.
+func (c *Command) run(ctx context.Context, args []string, help bool) error {
+	fs := c.flagSet()
 	fs.Usage = func() { Usage(c, fs) }
 
-	if c.Flags != nil {
-		c.Flags(fs)
+	if c.Help && !help {
+		args, help = c.stripHelp(args)
 	}
 
 	err := fs.Parse(args)
 	if err != nil {
-		return err
-	} else { //nolint: revive // keeps code of required-flag checks within a block
+		return unknownFlag(fs, err)
+	} else if !help { //nolint: revive // keeps code of required-flag checks within a block
 		placed := make([]string, 0, fs.NFlag())
 		fs.Visit(func(f *flag.Flag) { placed = append(placed, f.Name) })
 		for _, name := range c.FlagsRequired {
@@ -116,15 +209,31 @@ func (c *Command) Run(ctx context.Context, args []string) error {
 				return fmt.Errorf("missing required flag -%s", name)
 			}
 		}
+		if err := c.checkFlagGroups(placed, commandLineFlags(fs, args)); err != nil {
+			return err
+		}
 	}
 	args = fs.Args()
 
-	flags, _ := ctx.Value(ctxFlags{}).(map[string]*flag.Flag)
-	if flags == nil {
-		flags = make(map[string]*flag.Flag)
-		ctx = context.WithValue(ctx, ctxFlags{}, flags)
+	if c.Deprecated != "" && !help {
+		fmt.Fprintf(fs.Output(), "Command %q is deprecated, %s\n", c.Name, c.Deprecated)
+	}
+
+	s := &scope{parent: scopeFrom(ctx), command: c, flags: fs}
+	ctx = context.WithValue(ctx, ctxScope{}, s)
+
+	var sub *Command
+	if len(args) > 0 {
+		sub = c.subcommand(args[0])
+	}
+
+	if help {
+		if sub != nil {
+			return sub.run(ctx, args[1:], help)
+		}
+		fs.Usage()
+		return flag.ErrHelp
 	}
-	fs.VisitAll(func(f *flag.Flag) { flags[f.Name] = f })
 
 	runContext := defaultRunContext
 	if c.RunContext != nil {
@@ -132,12 +241,20 @@ func (c *Command) Run(ctx context.Context, args []string) error {
 	}
 
 	return runContext(ctx, func(child context.Context) error {
-		i := slices.IndexFunc(c.Subcommands, func(c *Command) bool { return len(args) > 0 && args[0] == c.Name })
 		switch {
-		case i != -1: // the remaining arguments matched a subcommand
-			return c.Subcommands[i].Run(child, args[1:])
+		case sub != nil: // the remaining arguments matched a subcommand
+			return sub.run(child, args[1:], help)
 		case c.Func != nil: // no subcommand could be run, fallback to this command action
+			parsed, err := c.parseArgs(args)
+			if err != nil {
+				fs.Usage()
+				return err
+			}
+			s.args = parsed
 			return c.Func(child, args)
+		case len(args) > 0 && len(c.subcommands()) > 0: // the remaining arguments did not match a subcommand
+			fs.Usage()
+			return c.unknownCommand(args[0])
 		default: // nothing could be done, print usage
 			fs.Usage()
 			return fmt.Errorf("cli cannot proceed with arguments %v", args)
@@ -145,26 +262,73 @@ func (c *Command) Run(ctx context.Context, args []string) error {
 	})
 }
 
+// subcommands returns the subcommands of the command, including the automatic help subcommand.
+func (c *Command) subcommands() []*Command {
+	if c.hasHelpCommand() {
+		return append(slices.Clip(c.Subcommands), helpEntry())
+	}
+	return c.Subcommands
+}
+
+// visibleSubcommands returns the subcommands of the command which are not hidden.
+func (c *Command) visibleSubcommands() []*Command {
+	return slices.DeleteFunc(slices.Clone(c.subcommands()), func(c *Command) bool { return c.Hidden })
+}
+
+// named reports whether the name or any alias of the command satisfies match.
+func (c *Command) named(match func(string) bool) bool {
+	return match(c.Name) || slices.ContainsFunc(c.Aliases, match)
+}
+
+// subcommand returns the subcommand matching name, or nil if there is none.
+func (c *Command) subcommand(name string) *Command {
+	subcommands := c.subcommands()
+	i := slices.IndexFunc(subcommands, func(c *Command) bool { return c.named(func(s string) bool { return s == name }) })
+	if i == -1 && c.PrefixMatching && name != "" {
+		for j, sub := range subcommands {
+			if sub.Hidden || !sub.named(func(s string) bool { return strings.HasPrefix(s, name) }) {
+				continue
+			}
+			if i != -1 { // ambiguous prefix
+				return nil
+			}
+			i = j
+		}
+	}
+
+	switch {
+	case i == -1:
+		return nil
+	case i == len(c.Subcommands): // only the automatic help subcommand follows the defined ones
+		return c.helpCommand()
+	default:
+		return subcommands[i]
+	}
+}
+
+// flagSet returns a new [flag.FlagSet] named after the command, with its flags defined.
+func (c *Command) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	if c.Flags != nil {
+		c.Flags(fs)
+	}
+	return fs
+}
+
 // defaultRunContext is the default implementation of [Command.RunContext].
 // It simply runs the callback without modifying anything.
 func defaultRunContext(parent context.Context, run func(ctx context.Context) error) error {
 	return run(parent)
 }
 
-type ctxFlags struct{}
-
 // Get looks for the named flag and returns its value.
+// Flags of subcommands shadow flags of their parents sharing the same name, see [Scope].
 // It returns nil if:
 //   - the specified [flag.Flag] was not found
 //   - its [flag.Value] does not implement [flag.Getter]
 //   - the [flag.Getter] itself returns nil
 func Get(ctx context.Context, name string) any {
-	flags, _ := ctx.Value(ctxFlags{}).(map[string]*flag.Flag)
-	if flags == nil {
-		return nil
-	}
-
-	f := flags[name]
+	f := lookupFlag(ctx, name)
 	if f == nil {
 		return nil
 	}
@@ -176,3 +340,80 @@ func Get(ctx context.Context, name string) any {
 
 	return g.Get()
 }
+
+// FlagNotFoundError is returned by [Lookup] when the named flag was not found.
+type FlagNotFoundError struct {
+	Name string
+}
+
+func (e *FlagNotFoundError) Error() string {
+	return fmt.Sprintf("flag -%s not found", e.Name)
+}
+
+// FlagNotGetterError is returned by [Lookup] when the [flag.Value] does not implement [flag.Getter].
+type FlagNotGetterError struct {
+	Name string
+}
+
+func (e *FlagNotGetterError) Error() string {
+	return fmt.Sprintf("flag -%s value does not implement flag.Getter", e.Name)
+}
+
+// FlagTypeError is returned by [Lookup] when the flag value is not of the requested type.
+type FlagTypeError struct {
+	Name  string
+	Value any
+	Type  reflect.Type
+}
+
+func (e *FlagTypeError) Error() string {
+	return fmt.Sprintf("flag -%s value is of type %T, not %v", e.Name, e.Value, e.Type)
+}
+
+// unwrapValue returns the [flag.Value] decorated by v, such as the values of
+// [github.com/rlibaert/flag/values.Track], or v itself if it decorates none.
+func unwrapValue(v flag.Value) flag.Value {
+	for {
+		u, ok := v.(interface{ Unwrap() flag.Value })
+		if !ok || u.Unwrap() == nil {
+			return v
+		}
+		v = u.Unwrap()
+	}
+}
+
+// Lookup looks for the named flag and returns its value as a T.
+// It returns an error if:
+//   - the specified [flag.Flag] was not found, see [FlagNotFoundError]
+//   - its [flag.Value] does not implement [flag.Getter], see [FlagNotGetterError]
+//   - the value returned by the [flag.Getter] is not a T, see [FlagTypeError]
+func Lookup[T any](ctx context.Context, name string) (T, error) {
+	var zero T
+
+	f := lookupFlag(ctx, name)
+	if f == nil {
+		return zero, &FlagNotFoundError{name}
+	}
+
+	g, ok := unwrapValue(f.Value).(flag.Getter)
+	if !ok {
+		return zero, &FlagNotGetterError{name}
+	}
+
+	value := g.Get()
+	v, ok := value.(T)
+	if !ok {
+		return zero, &FlagTypeError{name, value, reflect.TypeFor[T]()}
+	}
+
+	return v, nil
+}
+
+// MustGet is like [Lookup] but panics if an error occurs.
+func MustGet[T any](ctx context.Context, name string) T {
+	v, err := Lookup[T](ctx, name)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
@@ -6,11 +6,13 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/rlibaert/flag/cli"
+	"github.com/rlibaert/flag/values"
 )
 
 func ExampleUsage() {
@@ -28,8 +30,29 @@ func ExampleUsage() {
 				Usage: "subcommand 1",
 			},
 			{
-				Name:  "sub2",
-				Usage: "subcommand 2",
+				Name:    "sub2",
+				Aliases: []string{"s2"},
+				Usage:   "subcommand 2",
+			},
+			{
+				Name:   "sub3",
+				Usage:  "hidden subcommand 3",
+				Hidden: true,
+			},
+			{
+				Name:     "admin1",
+				Usage:    "admin subcommand 1",
+				Category: "Administration",
+			},
+			{
+				Name:     "debug1",
+				Usage:    "debug subcommand 1",
+				Category: "Debugging",
+			},
+			{
+				Name:     "admin2",
+				Usage:    "admin subcommand 2",
+				Category: "Administration",
 			},
 		},
 	}
@@ -52,8 +75,15 @@ func ExampleUsage() {
 	//     	an int flag (default 12)
 	//
 	// Commands:
-	//   sub1    subcommand 1
-	//   sub2    subcommand 2
+	//   sub1        subcommand 1
+	//   sub2, s2    subcommand 2
+	//
+	// Administration:
+	//   admin1      admin subcommand 1
+	//   admin2      admin subcommand 2
+	//
+	// Debugging:
+	//   debug1      debug subcommand 1
 }
 
 func TestCommandRun(t *testing.T) {
@@ -125,3 +155,221 @@ func TestGetValueNotFound(t *testing.T) {
 func TestGetNotCliContext(t *testing.T) {
 	require.Nil(t, cli.Get(context.Background(), "foo"))
 }
+
+func TestLookup(t *testing.T) {
+	c := cli.Command{
+		Flags: func(fs *flag.FlagSet) {
+			fs.Int("int", 12, "an int flag")
+			fs.Func("func", "a func flag", func(string) error { return nil })
+			fs.Var(values.Track(new(colorValue)), "tracked", "a tracked flag")
+			fs.Var(values.Track(values.Basic[int]()), "tracked-int", "a tracked int flag")
+		},
+		Func: func(ctx context.Context, _ []string) error {
+			v, err := cli.Lookup[int](ctx, "int")
+			require.NoError(t, err)
+			require.Equal(t, 42, v)
+			require.Equal(t, 42, cli.MustGet[int](ctx, "int"))
+
+			_, err = cli.Lookup[int](ctx, "foo")
+			require.ErrorAs(t, err, new(*cli.FlagNotFoundError))
+			require.EqualError(t, err, "flag -foo not found")
+
+			_, err = cli.Lookup[int](ctx, "func")
+			require.ErrorAs(t, err, new(*cli.FlagNotGetterError))
+			require.EqualError(t, err, "flag -func value does not implement flag.Getter")
+
+			_, err = cli.Lookup[int](ctx, "tracked")
+			require.ErrorAs(t, err, new(*cli.FlagNotGetterError))
+
+			v, err = cli.Lookup[int](ctx, "tracked-int")
+			require.NoError(t, err)
+			require.Equal(t, 7, v)
+
+			_, err = cli.Lookup[string](ctx, "int")
+			require.ErrorAs(t, err, new(*cli.FlagTypeError))
+			require.EqualError(t, err, "flag -int value is of type int, not string")
+
+			require.PanicsWithError(t, "flag -foo not found", func() { cli.MustGet[int](ctx, "foo") })
+			return errors.New("command terminated")
+		},
+	}
+	err := c.Run(context.Background(), []string{"-int", "42", "-tracked-int", "7"})
+	require.ErrorContains(t, err, "command terminated")
+}
+
+func TestLookupNotCliContext(t *testing.T) {
+	_, err := cli.Lookup[int](context.Background(), "foo")
+	require.ErrorAs(t, err, new(*cli.FlagNotFoundError))
+}
+
+func TestScope(t *testing.T) {
+	c := cli.Command{
+		Name: "root",
+		Flags: func(fs *flag.FlagSet) {
+			fs.String("config", "root.conf", "a shadowed flag")
+			fs.Int("int", 12, "an int flag")
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name: "sub",
+				Flags: func(fs *flag.FlagSet) {
+					fs.String("config", "sub.conf", "a shadowing flag")
+				},
+				Func: func(ctx context.Context, _ []string) error {
+					return fmt.Errorf("sub terminated: %v %v %v %v %v",
+						cli.Get(ctx, "config"),
+						cli.Get(cli.Scope(ctx), "config"),
+						cli.Get(cli.Scope(ctx, "sub"), "config"),
+						cli.Get(cli.Scope(ctx, "sub"), "int"),
+						cli.Get(cli.Scope(ctx, "other"), "config"),
+					)
+				},
+			},
+		},
+	}
+
+	err := c.Run(context.Background(), []string{"-config", "foo", "sub", "-config", "bar"})
+	require.EqualError(t, err, "sub terminated: bar foo bar 12 <nil>")
+}
+
+func TestValidate(t *testing.T) {
+	c := cli.Command{
+		Name: "root",
+		Flags: func(fs *flag.FlagSet) {
+			fs.String("config", "", "a shadowed flag")
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name: "sub",
+				Subcommands: []*cli.Command{
+					{
+						Name: "subsub",
+						Flags: func(fs *flag.FlagSet) {
+							fs.String("config", "", "a shadowing flag")
+						},
+					},
+				},
+			},
+			{
+				Name: "other",
+				Flags: func(fs *flag.FlagSet) {
+					fs.String("other", "", "a flag")
+				},
+			},
+		},
+	}
+
+	err := c.Validate()
+	require.EqualError(t, err, `flag -config of command "root sub subsub" shadows flag of command "root"`)
+
+	c.Subcommands = c.Subcommands[1:]
+	require.NoError(t, c.Validate())
+
+	c.Subcommands = append(c.Subcommands, &cli.Command{Name: "another", Aliases: []string{"other"}})
+	err = c.Validate()
+	require.EqualError(t, err, `subcommand name "other" of command "root" is used more than once`)
+}
+
+func TestCommandRunAliases(t *testing.T) {
+	c := cli.Command{
+		Subcommands: []*cli.Command{
+			{
+				Name:    "remove",
+				Aliases: []string{"rm", "delete"},
+				Func:    func(context.Context, []string) error { return errors.New("remove terminated") },
+			},
+			{
+				Name: "restore",
+				Func: func(context.Context, []string) error { return errors.New("restore terminated") },
+			},
+		},
+		Func: func(context.Context, []string) error { return errors.New("command terminated") },
+	}
+
+	testCases := []struct {
+		name           string
+		prefixMatching bool
+		args           []string
+		message        string
+	}{
+		{"name", false, []string{"remove"}, "remove terminated"},
+		{"alias", false, []string{"rm"}, "remove terminated"},
+		{"prefix disabled", false, []string{"rest"}, "command terminated"},
+		{"prefix", true, []string{"rest"}, "restore terminated"},
+		{"alias prefix", true, []string{"del"}, "remove terminated"},
+		{"ambiguous prefix", true, []string{"re"}, "command terminated"},
+		{"exact match over prefix", true, []string{"rm"}, "remove terminated"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c.PrefixMatching = tc.prefixMatching
+			require.EqualError(t, c.Run(context.Background(), tc.args), tc.message)
+		})
+	}
+}
+
+func TestCommandRunHiddenDeprecated(t *testing.T) {
+	b := strings.Builder{}
+	c := cli.Command{
+		Flags: func(fs *flag.FlagSet) {
+			fs.SetOutput(&b)
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:   "hidden",
+				Hidden: true,
+				Func:   func(context.Context, []string) error { return errors.New("hidden terminated") },
+			},
+			{
+				Name:       "old",
+				Deprecated: `use "new" instead`,
+				Flags:      func(fs *flag.FlagSet) { fs.SetOutput(&b) },
+				Func:       func(context.Context, []string) error { return errors.New("old terminated") },
+			},
+			{
+				Name: "new",
+			},
+		},
+	}
+
+	t.Run("runs hidden", func(t *testing.T) {
+		b.Reset()
+		require.EqualError(t, c.Run(context.Background(), []string{"hidden"}), "hidden terminated")
+		require.Empty(t, b.String())
+	})
+
+	t.Run("runs deprecated", func(t *testing.T) {
+		b.Reset()
+		require.EqualError(t, c.Run(context.Background(), []string{"old"}), "old terminated")
+		require.Equal(t, "Command \"old\" is deprecated, use \"new\" instead\n", b.String())
+	})
+
+	t.Run("does not complete hidden", func(t *testing.T) {
+		b.Reset()
+		require.NoError(t, c.Complete(&b, []string{""}))
+		require.Equal(t, "old\nnew\n", b.String())
+	})
+
+	t.Run("does not suggest hidden", func(t *testing.T) {
+		b.Reset()
+		require.EqualError(t, c.Run(context.Background(), []string{"hiden"}), `unknown command "hiden"`)
+	})
+}
+
+func TestCommandRunRequiredFromEnv(t *testing.T) {
+	t.Setenv("FOO_INT", "42")
+
+	c := cli.Command{
+		Flags: func(fs *flag.FlagSet) {
+			values.FlagSetEnvRegisterer(fs, "FOO_").Int("int", 12, "an int flag")
+		},
+		FlagsRequired: []string{"int"},
+		Func: func(ctx context.Context, _ []string) error {
+			return fmt.Errorf("command terminated: %v", cli.Get(ctx, "int"))
+		},
+	}
+
+	err := c.Run(context.Background(), []string{})
+	require.EqualError(t, err, "command terminated: 42")
+}
@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// completeArg is the hidden entrypoint called back by completion scripts.
+const completeArg = "__complete"
+
+// Completer is implemented by [flag.Value] able to provide completion candidates for their flag.
+type Completer interface {
+	// Complete returns the candidates matching the given prefix.
+	Complete(prefix string) []string
+}
+
+// Complete writes to w the completion candidates of the last argument of args, one per line.
+// The preceding arguments are used to walk the command tree, much like [Command.Run] does.
+//
+// Subcommand names are completed from [Command.Subcommands], flag names from the
+// [flag.FlagSet] built by [Command.Flags] and flag values from their [flag.Value]
+// if it implements [Completer].
+func (c *Command) Complete(w io.Writer, args []string) error {
+	prefix := ""
+	if len(args) > 0 {
+		args, prefix = args[:len(args)-1], args[len(args)-1]
+	}
+
+	for _, candidate := range c.complete(args, prefix) {
+		if _, err := fmt.Fprintln(w, candidate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Command) complete(args []string, prefix string) []string {
+	fs := c.flagSet()
+
+	for len(args) > 0 {
+		arg := args[0]
+		args = args[1:]
+
+		switch {
+		case arg == "--": // only positional arguments remain
+			return nil
+
+		case strings.HasPrefix(arg, "-"):
+			name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			f := fs.Lookup(name)
+			if f == nil || hasValue || isBoolFlag(f) {
+				continue
+			}
+			if len(args) == 0 { // the prefix is the value of this flag
+				return completeValue(f, "", prefix)
+			}
+			args = args[1:]
+
+		case arg == helpName && c.hasHelpCommand():
+			target, err := c.resolve(args)
+			if err != nil {
+				return nil
+			}
+			return target.completeSubcommands(prefix)
+
+		default:
+			sub := c.subcommand(arg)
+			if sub == nil { // positional arguments are not completed
+				return nil
+			}
+			return sub.complete(args, prefix)
+		}
+	}
+
+	candidates := []string{}
+	switch {
+	case strings.HasPrefix(prefix, "-"):
+		if name, value, ok := strings.Cut(prefix, "="); ok {
+			if f := fs.Lookup(strings.TrimLeft(name, "-")); f != nil {
+				return completeValue(f, name+"=", value)
+			}
+			return nil
+		}
+
+		dashes := "-"
+		if strings.HasPrefix(prefix, "--") {
+			dashes = "--"
+		}
+		fs.VisitAll(func(f *flag.Flag) {
+			if strings.HasPrefix(dashes+f.Name, prefix) {
+				candidates = append(candidates, dashes+f.Name)
+			}
+		})
+
+	default:
+		candidates = c.completeSubcommands(prefix)
+	}
+	return candidates
+}
+
+// completeSubcommands returns the names of the subcommands matching prefix.
+func (c *Command) completeSubcommands(prefix string) []string {
+	candidates := []string{}
+	for _, sub := range c.visibleSubcommands() {
+		if strings.HasPrefix(sub.Name, prefix) {
+			candidates = append(candidates, sub.Name)
+		}
+	}
+	return candidates
+}
+
+// completeValue returns the candidates of the flag value matching prefix, prepended with lead.
+func completeValue(f *flag.Flag, lead, prefix string) []string {
+	completer, ok := f.Value.(Completer)
+	if !ok {
+		return nil
+	}
+
+	candidates := completer.Complete(prefix)
+	for i := range candidates {
+		candidates[i] = lead + candidates[i]
+	}
+	return candidates
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+var completionScripts = map[string]string{ //nolint: gochecknoglobals // read-only templates
+	"bash": `# bash completion for %[1]s
+_%[2]s_complete() {
+	local IFS=$'\n'
+	COMPREPLY=($(%[1]s ` + completeArg + ` "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _%[2]s_complete %[1]s
+`,
+	"zsh": `#compdef %[1]s
+_%[2]s() {
+	local -a candidates
+	candidates=(${(f)"$(%[1]s ` + completeArg + ` "${(@)words[2,CURRENT]}" 2>/dev/null)"})
+	if (( ${#candidates} )); then
+		compadd -- $candidates
+	else
+		_files
+	fi
+}
+compdef _%[2]s %[1]s
+`,
+	"fish": `# fish completion for %[1]s
+complete -c %[1]s -a '(%[1]s ` + completeArg + ` (commandline -opc)[2..-1] (commandline -ct))'
+`,
+}
+
+var nonIdentifier = regexp.MustCompile(`[^A-Za-z0-9_]`) //nolint: gochecknoglobals // compiled once
+
+// Completion writes to w the completion script of the command for the given shell.
+// Supported shells are "bash", "zsh" and "fish".
+//
+// The script calls the program back with the hidden "__complete" entrypoint
+// handled by [Command.Run], so candidates are computed at runtime.
+func (c *Command) Completion(w io.Writer, shell string) error {
+	script, ok := completionScripts[shell]
+	if !ok {
+		return fmt.Errorf("unsupported shell %q", shell)
+	}
+
+	prog := filepath.Base(c.Name)
+	_, err := fmt.Fprintf(w, script, prog, nonIdentifier.ReplaceAllString(prog, "_"))
+	return err
+}
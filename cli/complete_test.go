@@ -0,0 +1,104 @@
+package cli_test
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/cli"
+)
+
+type colorValue string
+
+func (v *colorValue) Set(s string) error { *v = colorValue(s); return nil }
+func (v *colorValue) String() string     { return string(*v) }
+
+func (v *colorValue) Complete(prefix string) []string {
+	candidates := []string{}
+	for _, c := range []string{"red", "green", "blue"} {
+		if strings.HasPrefix(c, prefix) {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates
+}
+
+func completionCommand() *cli.Command {
+	return &cli.Command{
+		Name: "/usr/bin/foo-bar",
+		Flags: func(fs *flag.FlagSet) {
+			fs.Bool("verbose", false, "a bool flag")
+			fs.Var(new(colorValue), "color", "a completed flag")
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name: "serve",
+				Flags: func(fs *flag.FlagSet) {
+					fs.Int("port", 8080, "an int flag")
+				},
+			},
+			{Name: "status"},
+			{Name: "version"},
+		},
+	}
+}
+
+func ExampleCommand_Complete() {
+	c := completionCommand()
+	c.Complete(os.Stdout, []string{"-verbose", "s"})
+
+	// Output:
+	// serve
+	// status
+}
+
+func TestCommandComplete(t *testing.T) {
+	testCases := []struct {
+		name   string
+		args   []string
+		output string
+	}{
+		{"no arguments", nil, "serve\nstatus\nversion\n"},
+		{"subcommands", []string{"se"}, "serve\n"},
+		{"flags", []string{"-"}, "-color\n-verbose\n"},
+		{"double dash flags", []string{"--v"}, "--verbose\n"},
+		{"subcommand flags", []string{"-verbose", "serve", "-"}, "-port\n"},
+		{"flag value", []string{"-color", "r"}, "red\n"},
+		{"flag value inline", []string{"-color=g"}, "-color=green\n"},
+		{"flag value skipped", []string{"-color", "red", "v"}, "version\n"},
+		{"flag value not completed", []string{"serve", "-port", ""}, ""},
+		{"positional arguments", []string{"foo", ""}, ""},
+		{"terminated flags", []string{"--", ""}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := strings.Builder{}
+			require.NoError(t, completionCommand().Complete(&b, tc.args))
+			require.Equal(t, tc.output, b.String())
+		})
+	}
+}
+
+func TestCommandCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		t.Run(shell, func(t *testing.T) {
+			b := strings.Builder{}
+			require.NoError(t, completionCommand().Completion(&b, shell))
+			require.Contains(t, b.String(), "foo-bar __complete")
+		})
+	}
+
+	t.Run("bash function name", func(t *testing.T) {
+		b := strings.Builder{}
+		require.NoError(t, completionCommand().Completion(&b, "bash"))
+		require.Contains(t, b.String(), "complete -o default -F _foo_bar_complete foo-bar")
+	})
+
+	t.Run("unsupported shell", func(t *testing.T) {
+		require.EqualError(t, completionCommand().Completion(&strings.Builder{}, "csh"), `unsupported shell "csh"`)
+	})
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// walk calls fn for the command and each of its visible subcommands, depth first.
+// The path holds the commands from the root down to the visited one.
+func (c *Command) walk(path []*Command, fn func(path []*Command) error) error {
+	path = append(slices.Clip(path), c)
+	if err := fn(path); err != nil {
+		return err
+	}
+
+	for _, sub := range c.documentedSubcommands() {
+		if err := sub.walk(path, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// documentedSubcommands returns the visible subcommands, excluding the automatic help subcommand.
+func (c *Command) documentedSubcommands() []*Command {
+	return slices.DeleteFunc(slices.Clone(c.Subcommands), func(c *Command) bool { return c.Hidden })
+}
+
+// pathNames returns the names of the commands of path, using the base name of the root command.
+func pathNames(path []*Command) []string {
+	names := []string{}
+	for _, c := range path {
+		names = append(names, c.Name)
+	}
+	names[0] = filepath.Base(names[0])
+	return names
+}
+
+// summary returns the first line of the command usage.
+func (c *Command) summary() string {
+	summary, _, _ := strings.Cut(c.Usage, "\n")
+	return summary
+}
+
+// flagDefault returns the default value of the flag as printed by [flag.PrintDefaults],
+// or an empty string if it prints none, such as for zero values.
+func flagDefault(f *flag.Flag) string {
+	b := strings.Builder{}
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(&b)
+	fs.Var(f.Value, f.Name, "")
+	fs.Lookup(f.Name).DefValue = f.DefValue
+	fs.PrintDefaults()
+
+	_, def, ok := strings.Cut(b.String(), " (default ")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSuffix(def, ")\n")
+}
+
+// envAnnotation matches the environment variables annotating flag usages,
+// such as the ones added by [github.com/rlibaert/flag/values.FlagSetEnvRegisterer].
+var envAnnotation = regexp.MustCompile(`\(env (\$\w+(?:, \$\w+)*)\)`) //nolint: gochecknoglobals // compiled once
+
+// flagEnv returns the names of the environment variables found in the flag usage.
+func flagEnv(f *flag.Flag) []string {
+	names := []string{}
+	for _, m := range envAnnotation.FindAllStringSubmatch(f.Usage, -1) {
+		for _, name := range strings.Split(m[1], ", ") {
+			names = append(names, strings.TrimPrefix(name, "$"))
+		}
+	}
+	return names
+}
+
+// CommandDoc is the description of a [Command] for documentation purposes, see [Command.Describe].
+type CommandDoc struct {
+	Name        string        `json:"name"`
+	Path        string        `json:"path"`
+	Aliases     []string      `json:"aliases,omitempty"`
+	Category    string        `json:"category,omitempty"`
+	Deprecated  string        `json:"deprecated,omitempty"`
+	Usage       string        `json:"usage,omitempty"`
+	Synopsis    string        `json:"synopsis"`
+	Args        []ArgDoc      `json:"args,omitempty"`
+	Flags       []FlagDoc     `json:"flags,omitempty"`
+	Subcommands []*CommandDoc `json:"subcommands,omitempty"`
+}
+
+// FlagDoc is the description of a flag for documentation purposes, see [Command.Describe].
+type FlagDoc struct {
+	Name        string   `json:"name"`
+	Placeholder string   `json:"placeholder,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Default     string   `json:"default"`
+	Usage       string   `json:"usage"`
+	Required    bool     `json:"required,omitempty"` // listed in Command.FlagsRequired, regardless of flag groups
+	Env         []string `json:"env,omitempty"`
+}
+
+// ArgDoc is the description of a positional argument for documentation purposes, see [Command.Describe].
+type ArgDoc struct {
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`
+	Usage    string `json:"usage,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+	Variadic bool   `json:"variadic,omitempty"`
+}
+
+// Describe returns the description of the command and of its visible subcommands.
+//
+// The type of a flag or argument is the Go type of the value returned by its
+// [flag.Value], if it implements [flag.Getter]. Its placeholder and usage are the ones returned
+// by [flag.UnquoteUsage]. Required flags are the ones listed in [Command.FlagsRequired]:
+// flag groups, such as [Command.FlagsOneRequired], are not reflected.
+func (c *Command) Describe() *CommandDoc {
+	return describe([]*Command{c})
+}
+
+func describe(path []*Command) *CommandDoc {
+	c := path[len(path)-1]
+	names := pathNames(path)
+
+	doc := &CommandDoc{
+		Name:       names[len(names)-1],
+		Path:       strings.Join(names, " "),
+		Aliases:    c.Aliases,
+		Category:   c.Category,
+		Deprecated: c.Deprecated,
+		Usage:      c.Usage,
+		Synopsis:   strings.Join(append(slices.Clone(names), c.synopsis()...), " "),
+	}
+
+	for _, a := range c.Args {
+		ad := ArgDoc{Name: a.Name, Usage: a.Usage, Optional: a.Optional, Variadic: a.Variadic}
+		if a.Value != nil {
+			if g, ok := a.Value().(flag.Getter); ok {
+				ad.Type = fmt.Sprintf("%T", g.Get())
+			}
+		}
+		doc.Args = append(doc.Args, ad)
+	}
+
+	c.flagSet().VisitAll(func(f *flag.Flag) {
+		placeholder, usage := flag.UnquoteUsage(f)
+		fd := FlagDoc{
+			Name:        f.Name,
+			Placeholder: placeholder,
+			Default:     f.DefValue,
+			Usage:       usage,
+			Required:    slices.Contains(c.FlagsRequired, f.Name),
+			Env:         flagEnv(f),
+		}
+		if g, ok := f.Value.(flag.Getter); ok {
+			fd.Type = fmt.Sprintf("%T", g.Get())
+		}
+		doc.Flags = append(doc.Flags, fd)
+	})
+
+	for _, sub := range c.documentedSubcommands() {
+		doc.Subcommands = append(doc.Subcommands, describe(append(slices.Clip(path), sub)))
+	}
+
+	return doc
+}
+
+// JSON writes to w the indented JSON encoding of the command description, see [Command.Describe].
+func (c *Command) JSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(c.Describe())
+}
@@ -0,0 +1,141 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/cli"
+)
+
+func ExampleCommand_Markdown() {
+	c := manCommand()
+	c.FlagsRequired = []string{"config"}
+	c.Markdown(os.Stdout)
+
+	// Output:
+	// # app
+	//
+	// A sample application
+	//
+	// It does .things with \backslashes.
+	//
+	// ```
+	// app [options] COMMAND
+	// ```
+	//
+	// ## Options
+	//
+	// - `-config file` *(required)*: configuration file (env $APP_CONFIG) (default `"app.conf"`)
+	// - `-verbose`: enable verbose output
+	//
+	// ## Commands
+	//
+	// - [`serve`](app-serve.md), `s`: Start the server
+}
+
+func ExampleCommand_JSON() {
+	c := manCommand()
+	c.FlagsRequired = []string{"config"}
+	c.JSON(os.Stdout)
+
+	// Output:
+	// {
+	//   "name": "app",
+	//   "path": "app",
+	//   "usage": "A sample application\n\nIt does .things with \\backslashes.",
+	//   "synopsis": "app [options] COMMAND",
+	//   "flags": [
+	//     {
+	//       "name": "config",
+	//       "placeholder": "file",
+	//       "type": "string",
+	//       "default": "app.conf",
+	//       "usage": "configuration file (env $APP_CONFIG)",
+	//       "required": true,
+	//       "env": [
+	//         "APP_CONFIG"
+	//       ]
+	//     },
+	//     {
+	//       "name": "verbose",
+	//       "type": "bool",
+	//       "default": "false",
+	//       "usage": "enable verbose output"
+	//     }
+	//   ],
+	//   "subcommands": [
+	//     {
+	//       "name": "serve",
+	//       "path": "app serve",
+	//       "aliases": [
+	//         "s"
+	//       ],
+	//       "usage": "Start the server",
+	//       "synopsis": "app serve [options] [address]",
+	//       "flags": [
+	//         {
+	//           "name": "host",
+	//           "placeholder": "value",
+	//           "type": "string",
+	//           "default": "",
+	//           "usage": "host to listen on"
+	//         },
+	//         {
+	//           "name": "name",
+	//           "placeholder": "value",
+	//           "type": "string",
+	//           "default": "app",
+	//           "usage": "server name"
+	//         },
+	//         {
+	//           "name": "port",
+	//           "placeholder": "int",
+	//           "type": "int",
+	//           "default": "8080",
+	//           "usage": "port to listen on"
+	//         }
+	//       ]
+	//     }
+	//   ]
+	// }
+}
+
+func TestCommandMarkdownTree(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, manCommand().MarkdownTree(dir))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	require.Equal(t, []string{"app-serve.md", "app.md"}, names)
+
+	b, err := os.ReadFile(filepath.Join(dir, "app-serve.md"))
+	require.NoError(t, err)
+	require.Equal(t, "# app serve\n\nStart the server\n\n```\napp serve [options] [address]\n```\n\n"+
+		"Aliases: `s`\n\n## Options\n\n- `-host value`: host to listen on\n"+
+		"- `-name value`: server name (default `app`)\n- `-port int`: port to listen on (default `8080`)\n\n"+
+		"## See also\n\n- [`app`](app.md)\n", string(b))
+}
+
+func TestCommandMarkdownCategories(t *testing.T) {
+	c := cli.Command{
+		Name: "app",
+		Subcommands: []*cli.Command{
+			{Name: "serve", Category: "Server"},
+			{Name: "migrate", Category: "Database"},
+		},
+	}
+
+	b := strings.Builder{}
+	require.NoError(t, c.Markdown(&b))
+	require.Equal(t, "# app\n\n```\napp COMMAND\n```\n\n## Commands\n"+
+		"\n### Server\n\n- [`serve`](app-serve.md)\n"+
+		"\n### Database\n\n- [`migrate`](app-migrate.md)\n", b.String())
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// flagGroup is a group of flags sharing a constraint.
+type flagGroup struct {
+	names    []string
+	message  string                     // description of the constraint, reported when it is not met
+	check    func(placed []string) bool // whether the placed flags of the group meet the constraint
+	annotate func(name string) string   // annotation of the usage of the named flag, if any
+}
+
+// flagGroups returns the flag groups of the command, in order of verification.
+func (c *Command) flagGroups() []flagGroup {
+	groups := []flagGroup{}
+	for _, names := range c.FlagsMutuallyExclusive {
+		groups = append(groups, flagGroup{
+			names:    names,
+			message:  "at most one of " + flagList(names) + " flags must be set",
+			check:    func(placed []string) bool { return len(placed) <= 1 },
+			annotate: func(name string) string { return "conflicts with " + flagList(others(names, name)) },
+		})
+	}
+	for _, names := range c.FlagsOneRequired {
+		groups = append(groups, flagGroup{
+			names:    names,
+			message:  "at least one of " + flagList(names) + " flags must be set",
+			check:    func(placed []string) bool { return len(placed) >= 1 },
+			annotate: func(string) string { return "one of " + flagList(names) + " required" },
+		})
+	}
+	for _, names := range c.FlagsRequiredTogether {
+		groups = append(groups, flagGroup{
+			names:    names,
+			message:  "all or none of " + flagList(names) + " flags must be set",
+			check:    func(placed []string) bool { return len(placed) == 0 || len(placed) == len(names) },
+			annotate: func(name string) string { return "requires " + flagList(others(names, name)) },
+		})
+	}
+	for _, names := range c.FlagsDependencies {
+		if len(names) == 0 {
+			continue
+		}
+		groups = append(groups, flagGroup{
+			names:   names,
+			message: flagList(names[:1]) + " requires " + flagList(names[1:]) + " flags to be set",
+			check: func(placed []string) bool {
+				return !slices.Contains(placed, names[0]) || len(placed) == len(names)
+			},
+			annotate: func(name string) string {
+				if name == names[0] {
+					return "requires " + flagList(names[1:])
+				}
+				return "required by " + flagList(names[:1])
+			},
+		})
+	}
+	return groups
+}
+
+// flagList formats the flag names as a comma-separated list, e.g. "-a, -b".
+func flagList(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return "-" + strings.Join(names, ", -")
+}
+
+// others returns the names other than name.
+func others(names []string, name string) []string {
+	return slices.DeleteFunc(slices.Clone(names), func(n string) bool { return n == name })
+}
+
+// checkFlagGroups checks the placed flags against the flag groups of the command.
+// The flags of a group set on the command line override the ones set by other sources,
+// which are only considered if none is. Errors list the considered flags of the group.
+func (c *Command) checkFlagGroups(placed, commandLine []string) error {
+	for _, g := range c.flagGroups() {
+		in := slices.DeleteFunc(slices.Clone(g.names), func(name string) bool {
+			return !slices.Contains(commandLine, name)
+		})
+		if len(in) == 0 {
+			in = slices.DeleteFunc(slices.Clone(g.names), func(name string) bool { return !slices.Contains(placed, name) })
+		}
+		if !g.check(in) {
+			return fmt.Errorf("%s, got %s", g.message, flagList(in))
+		}
+	}
+	return nil
+}
+
+// flagRecorder implements [flag.Value] accepting any value, so that [flag.FlagSet.Visit] reports its flag.
+type flagRecorder struct {
+	isBool bool
+}
+
+func (v flagRecorder) Set(string) error { return nil }
+func (v flagRecorder) String() string   { return "" }
+func (v flagRecorder) IsBoolFlag() bool { return v.isBool }
+
+// commandLineFlags returns the names of the flags of fs set by args, once successfully parsed,
+// as opposed to the ones set beforehand by other sources, such as environment variables.
+func commandLineFlags(fs *flag.FlagSet, args []string) []string {
+	recorder := flag.NewFlagSet(fs.Name(), flag.ContinueOnError)
+	recorder.SetOutput(io.Discard)
+	fs.VisitAll(func(f *flag.Flag) { recorder.Var(flagRecorder{isBoolFlag(f)}, f.Name, "") })
+	_ = recorder.Parse(args) // args were successfully parsed by fs
+
+	names := []string{}
+	recorder.Visit(func(f *flag.Flag) { names = append(names, f.Name) })
+	return names
+}
+
+// annotatedFlags returns fs, or a copy of fs whose flag usages are annotated with
+// the constraints of the flag groups of the command, for [flag.FlagSet.PrintDefaults].
+func (c *Command) annotatedFlags(fs *flag.FlagSet) *flag.FlagSet {
+	groups := c.flagGroups()
+	if len(groups) == 0 {
+		return fs
+	}
+
+	annotated := flag.NewFlagSet(fs.Name(), flag.ContinueOnError)
+	annotated.SetOutput(fs.Output())
+	fs.VisitAll(func(f *flag.Flag) {
+		annotations := []string{}
+		for _, g := range groups {
+			if slices.Contains(g.names, f.Name) {
+				annotations = append(annotations, g.annotate(f.Name))
+			}
+		}
+
+		usage := f.Usage
+		if len(annotations) > 0 {
+			usage += " (" + strings.Join(annotations, "; ") + ")"
+		}
+		annotated.Var(f.Value, f.Name, usage)
+		annotated.Lookup(f.Name).DefValue = f.DefValue
+	})
+	return annotated
+}
+
+// validateFlags checks that required and grouped flags are defined in fs.
+func (c *Command) validateFlags(path string, fs *flag.FlagSet) error {
+	names := slices.Clone(c.FlagsRequired)
+	for _, g := range c.flagGroups() {
+		names = append(names, g.names...)
+	}
+
+	errs := []error{}
+	for _, name := range names {
+		if fs.Lookup(name) == nil {
+			errs = append(errs, fmt.Errorf("flag -%s of command %q is constrained but not defined", name, path))
+		}
+	}
+	return errors.Join(errs...)
+}
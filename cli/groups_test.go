@@ -0,0 +1,136 @@
+package cli_test
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/cli"
+	"github.com/rlibaert/flag/values"
+)
+
+func groupsCommand() *cli.Command {
+	return &cli.Command{
+		Name: "foo",
+		Flags: func(fs *flag.FlagSet) {
+			fs.SetOutput(io.Discard)
+			fs.String("file", "", "read from file")
+			fs.String("url", "", "read from URL")
+			fs.Bool("stdin", false, "read from standard input")
+			fs.Bool("json", false, "JSON output")
+			fs.Bool("yaml", false, "YAML output")
+			fs.String("tls-cert", "", "TLS certificate")
+			fs.String("tls-key", "", "TLS key")
+			fs.String("tls-ca", "", "TLS certificate authority")
+		},
+		FlagsMutuallyExclusive: [][]string{{"file", "url", "stdin"}, {"json", "yaml"}},
+		FlagsOneRequired:       [][]string{{"file", "url", "stdin"}},
+		FlagsRequiredTogether:  [][]string{{"tls-cert", "tls-key"}},
+		FlagsDependencies:      [][]string{{"tls-ca", "tls-cert"}},
+		Func:                   func(context.Context, []string) error { return nil },
+	}
+}
+
+func ExampleCommand_flagGroups() {
+	c := groupsCommand()
+	c.UsageArgs = "[args...]"
+
+	fs := flag.NewFlagSet("", flag.PanicOnError)
+	c.Flags(fs)
+	fs.SetOutput(os.Stdout)
+
+	cli.Usage(c, fs)
+
+	// Output:
+	// Usage: foo [options] [args...]
+	//
+	// Options:
+	//   -file string
+	//     	read from file (conflicts with -url, -stdin; one of -file, -url, -stdin required)
+	//   -json
+	//     	JSON output (conflicts with -yaml)
+	//   -stdin
+	//     	read from standard input (conflicts with -file, -url; one of -file, -url, -stdin required)
+	//   -tls-ca string
+	//     	TLS certificate authority (requires -tls-cert)
+	//   -tls-cert string
+	//     	TLS certificate (requires -tls-key; required by -tls-ca)
+	//   -tls-key string
+	//     	TLS key (requires -tls-cert)
+	//   -url string
+	//     	read from URL (conflicts with -file, -stdin; one of -file, -url, -stdin required)
+	//   -yaml
+	//     	YAML output (conflicts with -json)
+}
+
+func TestCommandRunFlagGroups(t *testing.T) {
+	testCases := []struct {
+		name    string
+		args    []string
+		message string
+	}{
+		{"exactly one", []string{"-url", "http://example.com", "-json"}, ""},
+		{"none", []string{}, "at least one of -file, -url, -stdin flags must be set, got none"},
+		{"too many", []string{"-stdin", "-file", "foo"},
+			"at most one of -file, -url, -stdin flags must be set, got -file, -stdin"},
+		{"exclusive", []string{"-stdin", "-json", "-yaml"},
+			"at most one of -json, -yaml flags must be set, got -json, -yaml"},
+		{"together", []string{"-stdin", "-tls-cert", "foo", "-tls-key", "bar"}, ""},
+		{"not together", []string{"-stdin", "-tls-key", "bar"},
+			"all or none of -tls-cert, -tls-key flags must be set, got -tls-key"},
+		{"dependency", []string{"-stdin", "-tls-ca", "ca", "-tls-cert", "foo", "-tls-key", "bar"}, ""},
+		{"dependency alone", []string{"-stdin", "-tls-ca", "ca"},
+			"-tls-ca requires -tls-cert flags to be set, got -tls-ca"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := groupsCommand().Run(context.Background(), tc.args)
+			if tc.message == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.message)
+			}
+		})
+	}
+}
+
+func TestValidateFlagGroups(t *testing.T) {
+	c := groupsCommand()
+	require.NoError(t, c.Validate())
+
+	c.FlagsRequired = []string{"output"}
+	c.FlagsRequiredTogether = append(c.FlagsRequiredTogether, []string{"user", "password"})
+	require.EqualError(t, c.Validate(), "flag -output of command \"foo\" is constrained but not defined\n"+
+		"flag -user of command \"foo\" is constrained but not defined\n"+
+		"flag -password of command \"foo\" is constrained but not defined")
+}
+
+func TestCommandRunFlagGroupsFromEnv(t *testing.T) {
+	c := func() *cli.Command {
+		return &cli.Command{
+			Flags: func(fs *flag.FlagSet) {
+				fs.SetOutput(io.Discard)
+				reg := values.FlagSetEnvRegisterer(fs, "FOO_")
+				reg.Bool("json", false, "JSON output")
+				reg.Bool("yaml", false, "YAML output")
+			},
+			FlagsMutuallyExclusive: [][]string{{"json", "yaml"}},
+			Func:                   func(context.Context, []string) error { return nil },
+		}
+	}
+
+	t.Setenv("FOO_JSON", "true")
+	require.NoError(t, c().Run(context.Background(), []string{}))
+	require.NoError(t, c().Run(context.Background(), []string{"-yaml=true"}), "command line overrides env")
+	require.EqualError(t, c().Run(context.Background(), []string{"-json=true", "-yaml=true"}),
+		"at most one of -json, -yaml flags must be set, got -json, -yaml")
+
+	t.Setenv("FOO_YAML", "true")
+	require.EqualError(t, c().Run(context.Background(), []string{}),
+		"at most one of -json, -yaml flags must be set, got -json, -yaml")
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"slices"
+	"strings"
+)
+
+// helpName is the name of the automatic help subcommand, see [Command.Help].
+const helpName = "help"
+
+// hasHelpCommand reports whether the command has the automatic help subcommand,
+// which is the case when [Command.Help] is set and no other subcommand shares its name.
+func (c *Command) hasHelpCommand() bool {
+	return c.Help && !slices.ContainsFunc(c.Subcommands, func(c *Command) bool { return c.Name == helpName })
+}
+
+// helpEntry returns the definition of the automatic help subcommand, without its function.
+// It is used for listing the subcommand, see [Command.helpCommand] for running it.
+func helpEntry() *Command {
+	return &Command{
+		Name:      helpName,
+		Usage:     "Show help for a command",
+		UsageArgs: "[COMMAND...]",
+	}
+}
+
+// helpCommand returns the automatic help subcommand of the command.
+func (c *Command) helpCommand() *Command {
+	help := helpEntry()
+	help.Func = func(ctx context.Context, args []string) error {
+		target, err := c.resolve(args)
+		if err != nil {
+			return err
+		}
+
+		fs := target.flagSet()
+		fs.SetOutput(scopeFrom(ctx).parent.flags.Output())
+		Usage(target, fs)
+		return nil
+	}
+	return help
+}
+
+// resolve returns the command found by following the subcommand names of path.
+func (c *Command) resolve(path []string) (*Command, error) {
+	for _, name := range path {
+		sub := c.subcommand(name)
+		if sub == nil {
+			return nil, c.unknownCommand(name)
+		}
+		c = sub
+	}
+	return c, nil
+}
+
+// stripHelp removes the help flags from args, stopping at the "--" terminator.
+// It reports whether any help flag was found.
+//
+// The arguments are walked down the command tree, much like [Command.Complete] does,
+// so that values of non-boolean flags and help flags defined by a command are kept.
+func (c *Command) stripHelp(args []string) ([]string, bool) {
+	fs := c.flagSet()
+	stripped := make([]string, 0, len(args))
+	found, positional := false, false
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return append(stripped, args[i:]...), found
+
+		case isHelpFlag(arg) && (positional || fs.Lookup(strings.TrimLeft(arg, "-")) == nil):
+			found = true
+			continue
+
+		case positional: // flags are not parsed past positional arguments, only help flags are stripped
+
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			if takesValue(fs, arg) && i+1 < len(args) {
+				stripped = append(stripped, arg)
+				i++
+				arg = args[i]
+			}
+
+		default:
+			positional = true
+			if sub := c.subcommand(arg); sub != nil {
+				rest, subFound := sub.stripHelp(args[i+1:])
+				return append(append(stripped, arg), rest...), found || subFound
+			}
+		}
+		stripped = append(stripped, arg)
+	}
+	return stripped, found
+}
+
+func isHelpFlag(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--h" || arg == "--help"
+}
+
+// takesValue reports whether the flag argument arg expects its value in the next argument.
+func takesValue(fs *flag.FlagSet, arg string) bool {
+	name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	f := fs.Lookup(name)
+	return f != nil && !hasValue && !isBoolFlag(f)
+}
@@ -0,0 +1,154 @@
+package cli_test
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/cli"
+)
+
+func helpCommand(output *strings.Builder) *cli.Command {
+	return &cli.Command{
+		Name: "foo",
+		Help: true,
+		Flags: func(fs *flag.FlagSet) {
+			fs.SetOutput(output)
+			fs.Int("int", 12, "an int flag")
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:          "sub",
+				Usage:         "subcommand",
+				UsageArgs:     "args...",
+				FlagsRequired: []string{"bool"},
+				Flags: func(fs *flag.FlagSet) {
+					fs.SetOutput(output)
+					fs.Bool("bool", false, "a bool flag")
+				},
+				Func: func(context.Context, []string) error { return errors.New("sub terminated") },
+			},
+		},
+	}
+}
+
+func ExampleCommand_help() {
+	c := cli.Command{
+		Name:      "foo",
+		UsageArgs: "[args...]",
+		Help:      true,
+		Flags: func(fs *flag.FlagSet) {
+			fs.SetOutput(os.Stdout)
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:  "sub",
+				Usage: "subcommand",
+			},
+		},
+	}
+	c.Run(context.Background(), []string{"help"})
+
+	// Output:
+	// Usage: foo [options] COMMAND [args...]
+	//
+	// Options:
+	//
+	// Commands:
+	//   sub     subcommand
+	//   help    Show help for a command
+}
+
+func TestCommandHelp(t *testing.T) {
+	const subUsage = "Usage: sub [options] args...\n\nsubcommand\n\nOptions:\n  -bool\n    \ta bool flag\n"
+
+	t.Run("help subcommand", func(t *testing.T) {
+		b := strings.Builder{}
+		require.NoError(t, helpCommand(&b).Run(context.Background(), []string{"help", "sub"}))
+		require.Equal(t, subUsage, b.String())
+	})
+
+	t.Run("help subcommand unknown command", func(t *testing.T) {
+		b := strings.Builder{}
+		err := helpCommand(&b).Run(context.Background(), []string{"help", "bar"})
+		require.EqualError(t, err, `unknown command "bar"`)
+	})
+
+	t.Run("help flag after subcommand arguments", func(t *testing.T) {
+		b := strings.Builder{}
+		err := helpCommand(&b).Run(context.Background(), []string{"-int", "42", "sub", "foo", "--help"})
+		require.ErrorIs(t, err, flag.ErrHelp)
+		require.Equal(t, subUsage, b.String())
+	})
+
+	t.Run("help flag before subcommand", func(t *testing.T) {
+		b := strings.Builder{}
+		err := helpCommand(&b).Run(context.Background(), []string{"-h", "sub"})
+		require.ErrorIs(t, err, flag.ErrHelp)
+		require.Equal(t, subUsage, b.String())
+	})
+
+	t.Run("help flag after terminator", func(t *testing.T) {
+		b := strings.Builder{}
+		err := helpCommand(&b).Run(context.Background(), []string{"sub", "-bool", "--", "-h"})
+		require.EqualError(t, err, "sub terminated")
+		require.Empty(t, b.String())
+	})
+
+	t.Run("help flag as flag value", func(t *testing.T) {
+		var msg string
+		c := &cli.Command{
+			Name: "foo",
+			Help: true,
+			Flags: func(fs *flag.FlagSet) {
+				fs.String("msg", "", "a string flag")
+				fs.Bool("bool", false, "a bool flag")
+			},
+			Func: func(ctx context.Context, _ []string) error {
+				msg = cli.MustGet[string](ctx, "msg")
+				return nil
+			},
+		}
+		require.NoError(t, c.Run(context.Background(), []string{"-msg", "-h"}))
+		require.Equal(t, "-h", msg)
+		require.NoError(t, c.Run(context.Background(), []string{"-bool", "-msg=-x", "--msg", "--help"}))
+		require.Equal(t, "--help", msg)
+		require.ErrorIs(t, c.Run(context.Background(), []string{"-bool", "-h"}), flag.ErrHelp)
+	})
+
+	t.Run("help flag defined by command", func(t *testing.T) {
+		var host string
+		b := strings.Builder{}
+		c := &cli.Command{
+			Name: "foo",
+			Help: true,
+			Flags: func(fs *flag.FlagSet) {
+				fs.SetOutput(&b)
+				fs.String("h", "localhost", "a host flag")
+			},
+			Subcommands: []*cli.Command{
+				{
+					Name: "sub",
+					Func: func(ctx context.Context, _ []string) error {
+						host = cli.MustGet[string](ctx, "h")
+						return nil
+					},
+				},
+			},
+		}
+		require.NoError(t, c.Run(context.Background(), []string{"-h", "example.com", "sub"}))
+		require.Equal(t, "example.com", host)
+		require.ErrorIs(t, c.Run(context.Background(), []string{"-h", "example.com", "sub", "-h"}), flag.ErrHelp)
+	})
+
+	t.Run("completes help subcommand", func(t *testing.T) {
+		b := strings.Builder{}
+		require.NoError(t, helpCommand(nil).Complete(&b, []string{"help", ""}))
+		require.Equal(t, "sub\nhelp\n", b.String())
+	})
+}
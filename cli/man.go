@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Man writes to w the man page of the command in roff format, for the given manual section.
+//
+// The page documents the name, synopsis and description of the command, its options
+// with their defaults, its visible subcommands and the environment variables
+// annotating option usages, such as the ones added by [github.com/rlibaert/flag/values.FlagSetEnvRegisterer].
+func (c *Command) Man(w io.Writer, section string) error {
+	return writeMan(w, []*Command{c}, section)
+}
+
+// ManTree writes to dir the man pages of the command and of each of its visible subcommands,
+// for the given manual section. Pages are named after the command path joined with dashes,
+// e.g. "app-serve.1".
+func (c *Command) ManTree(dir, section string) error {
+	return c.walk(nil, func(path []*Command) error {
+		f, err := os.Create(filepath.Join(dir, strings.Join(pathNames(path), "-")+"."+section))
+		if err != nil {
+			return err
+		}
+		return errors.Join(writeMan(f, path, section), f.Close())
+	})
+}
+
+func writeMan(w io.Writer, path []*Command, section string) error {
+	c := path[len(path)-1]
+	fs := c.flagSet()
+	names := pathNames(path)
+	page := strings.Join(names, "-")
+
+	b := strings.Builder{}
+	fmt.Fprintf(&b, ".TH %s %s\n", roffArg(strings.ToUpper(page)), roffArg(section))
+
+	b.WriteString(".SH NAME\n")
+	b.WriteString(roff(page))
+	if summary := c.summary(); summary != "" {
+		b.WriteString(` \- ` + roff(summary))
+	}
+	b.WriteString("\n")
+
+	b.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(&b, ".B %s\n", roff(strings.Join(names, " ")))
+	if synopsis := c.synopsis(); len(synopsis) > 0 {
+		b.WriteString(roff(strings.Join(synopsis, " ")) + "\n")
+	}
+
+	if c.Usage != "" {
+		b.WriteString(".SH DESCRIPTION\n")
+		b.WriteString(strings.ReplaceAll(roff(c.Usage), "\n\n", "\n.PP\n") + "\n")
+	}
+
+	if len(c.Args) > 0 {
+		b.WriteString(".SH ARGUMENTS\n")
+		for _, a := range c.Args {
+			fmt.Fprintf(&b, ".TP\n\\fI%s\\fR\n%s\n", roff(a.placeholder()), roff(a.Usage))
+		}
+	}
+
+	if c.Flags != nil {
+		b.WriteString(".SH OPTIONS\n")
+		fs.VisitAll(func(f *flag.Flag) { writeManOption(&b, f) })
+	}
+
+	subcommands := c.documentedSubcommands()
+	if len(subcommands) > 0 {
+		b.WriteString(".SH COMMANDS\n")
+		for _, sub := range subcommands {
+			fmt.Fprintf(&b, ".TP\n\\fB%s\\fR\n", roff(strings.Join(append([]string{sub.Name}, sub.Aliases...), ", ")))
+			b.WriteString(roff(sub.summary()) + "\n")
+		}
+	}
+
+	writeManEnvironment(&b, fs)
+
+	seeAlso := []string{}
+	if len(path) > 1 {
+		seeAlso = append(seeAlso, strings.Join(names[:len(names)-1], "-"))
+	}
+	for _, sub := range subcommands {
+		seeAlso = append(seeAlso, page+"-"+sub.Name)
+	}
+	writeManSeeAlso(&b, seeAlso, section)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeManOption(b *strings.Builder, f *flag.Flag) {
+	name, usage := flag.UnquoteUsage(f)
+	fmt.Fprintf(b, ".TP\n\\fB\\-%s\\fR", roff(f.Name))
+	if name != "" {
+		fmt.Fprintf(b, ` \fI%s\fR`, roff(name))
+	}
+	b.WriteString("\n" + roff(usage))
+	if def := flagDefault(f); def != "" {
+		b.WriteString(" (default " + roff(def) + ")")
+	}
+	b.WriteString("\n")
+}
+
+func writeManEnvironment(b *strings.Builder, fs *flag.FlagSet) {
+	env := []string{}
+	fs.VisitAll(func(f *flag.Flag) {
+		for _, name := range flagEnv(f) {
+			env = append(env, fmt.Sprintf(".TP\n\\fB%s\\fR\nSee the \\fB\\-%s\\fR option.\n", roff(name), roff(f.Name)))
+		}
+	})
+	if len(env) > 0 {
+		b.WriteString(".SH ENVIRONMENT\n")
+		b.WriteString(strings.Join(env, ""))
+	}
+}
+
+func writeManSeeAlso(b *strings.Builder, pages []string, section string) {
+	if len(pages) == 0 {
+		return
+	}
+	b.WriteString(".SH SEE ALSO\n")
+	for i, page := range pages {
+		sep := ","
+		if i == len(pages)-1 {
+			sep = ""
+		}
+		fmt.Fprintf(b, ".BR %s (%s)%s\n", roff(page), section, sep)
+	}
+}
+
+// roffArg escapes and quotes s for use as a roff macro argument.
+func roffArg(s string) string {
+	return `"` + strings.ReplaceAll(roff(s), `"`, `""`) + `"`
+}
+
+// roff escapes s for use as roff text.
+func roff(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,111 @@
+package cli_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/cli"
+	"github.com/rlibaert/flag/values"
+)
+
+func manCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "/usr/bin/app",
+		Usage: "A sample application\n\nIt does .things with \\backslashes.",
+		Flags: func(fs *flag.FlagSet) {
+			fs.Bool("verbose", false, "enable verbose output")
+			fs.String("config", "app.conf", "configuration `file` (env $APP_CONFIG)")
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:      "serve",
+				Aliases:   []string{"s"},
+				Usage:     "Start the server",
+				UsageArgs: "[address]",
+				Flags: func(fs *flag.FlagSet) {
+					fs.Int("port", 8080, "port to listen on")
+					fs.Var(values.Track(values.Basic[string]()), "host", "host to listen on")
+					values.FlagSetRegisterer(fs).Tracked().String("name", "app", "server name")
+				},
+			},
+			{
+				Name:   "debug",
+				Hidden: true,
+			},
+		},
+	}
+}
+
+func ExampleCommand_Man() {
+	manCommand().Man(os.Stdout, "1")
+
+	// Output:
+	// .TH "APP" "1"
+	// .SH NAME
+	// app \- A sample application
+	// .SH SYNOPSIS
+	// .B app
+	// [options] COMMAND
+	// .SH DESCRIPTION
+	// A sample application
+	// .PP
+	// It does .things with \ebackslashes.
+	// .SH OPTIONS
+	// .TP
+	// \fB\-config\fR \fIfile\fR
+	// configuration file (env $APP_CONFIG) (default "app.conf")
+	// .TP
+	// \fB\-verbose\fR
+	// enable verbose output
+	// .SH COMMANDS
+	// .TP
+	// \fBserve, s\fR
+	// Start the server
+	// .SH ENVIRONMENT
+	// .TP
+	// \fBAPP_CONFIG\fR
+	// See the \fB\-config\fR option.
+	// .SH SEE ALSO
+	// .BR app\-serve (1)
+}
+
+func TestCommandManTree(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, manCommand().ManTree(dir, "8"))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	require.Equal(t, []string{"app-serve.8", "app.8"}, names)
+
+	b, err := os.ReadFile(filepath.Join(dir, "app-serve.8"))
+	require.NoError(t, err)
+	require.Equal(t, `.TH "APP\-SERVE" "8"
+.SH NAME
+app\-serve \- Start the server
+.SH SYNOPSIS
+.B app serve
+[options] [address]
+.SH DESCRIPTION
+Start the server
+.SH OPTIONS
+.TP
+\fB\-host\fR \fIvalue\fR
+host to listen on
+.TP
+\fB\-name\fR \fIvalue\fR
+server name (default app)
+.TP
+\fB\-port\fR \fIint\fR
+port to listen on (default 8080)
+.SH SEE ALSO
+.BR app (8)
+`, string(b))
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Markdown writes to w the Markdown reference page of the command.
+//
+// The page documents the synopsis and description of the command, its options
+// with their defaults and its visible subcommands, linking to their pages as
+// named by [Command.MarkdownTree].
+func (c *Command) Markdown(w io.Writer) error {
+	return writeMarkdown(w, []*Command{c})
+}
+
+// MarkdownTree writes to dir the Markdown reference pages of the command and of each
+// of its visible subcommands. Pages are named after the command path joined with
+// dashes, e.g. "app-serve.md".
+func (c *Command) MarkdownTree(dir string) error {
+	return c.walk(nil, func(path []*Command) error {
+		f, err := os.Create(filepath.Join(dir, strings.Join(pathNames(path), "-")+".md"))
+		if err != nil {
+			return err
+		}
+		return errors.Join(writeMarkdown(f, path), f.Close())
+	})
+}
+
+func writeMarkdown(w io.Writer, path []*Command) error {
+	c := path[len(path)-1]
+	names := pathNames(path)
+	page := strings.Join(names, "-")
+
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "# %s\n", strings.Join(names, " "))
+
+	if c.Deprecated != "" {
+		fmt.Fprintf(&b, "\n> **Deprecated:** %s\n", c.Deprecated)
+	}
+
+	if c.Usage != "" {
+		fmt.Fprintf(&b, "\n%s\n", c.Usage)
+	}
+
+	fmt.Fprintf(&b, "\n```\n%s\n```\n", strings.Join(append(slices.Clone(names), c.synopsis()...), " "))
+
+	if len(c.Aliases) > 0 {
+		fmt.Fprintf(&b, "\nAliases: `%s`\n", strings.Join(c.Aliases, "`, `"))
+	}
+
+	if len(c.Args) > 0 {
+		b.WriteString("\n## Arguments\n\n")
+		for _, a := range c.Args {
+			fmt.Fprintf(&b, "- `%s`", a.placeholder())
+			if a.Usage != "" {
+				b.WriteString(": " + a.Usage)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if c.Flags != nil {
+		b.WriteString("\n## Options\n\n")
+		c.flagSet().VisitAll(func(f *flag.Flag) { writeMarkdownOption(&b, c, f) })
+	}
+
+	subcommands := c.documentedSubcommands()
+	if len(subcommands) > 0 {
+		b.WriteString("\n## Commands\n")
+		categories := []string{""}
+		for _, sub := range subcommands {
+			if !slices.Contains(categories, sub.Category) {
+				categories = append(categories, sub.Category)
+			}
+		}
+		for _, category := range categories {
+			if !slices.ContainsFunc(subcommands, func(sub *Command) bool { return sub.Category == category }) {
+				continue
+			}
+			if category != "" {
+				fmt.Fprintf(&b, "\n### %s\n", category)
+			}
+			b.WriteString("\n")
+			for _, sub := range subcommands {
+				if sub.Category == category {
+					writeMarkdownCommand(&b, page, sub)
+				}
+			}
+		}
+	}
+
+	if len(path) > 1 {
+		parent := names[:len(names)-1]
+		fmt.Fprintf(&b, "\n## See also\n\n- [`%s`](%s.md)\n", strings.Join(parent, " "), strings.Join(parent, "-"))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownOption(b *strings.Builder, c *Command, f *flag.Flag) {
+	name, usage := flag.UnquoteUsage(f)
+	if name != "" {
+		name = " " + name
+	}
+	fmt.Fprintf(b, "- `-%s%s`", f.Name, name)
+	if slices.Contains(c.FlagsRequired, f.Name) {
+		b.WriteString(" *(required)*")
+	}
+	b.WriteString(": " + strings.ReplaceAll(usage, "\n", " "))
+	if def := flagDefault(f); def != "" {
+		fmt.Fprintf(b, " (default `%s`)", def)
+	}
+	b.WriteString("\n")
+}
+
+func writeMarkdownCommand(b *strings.Builder, page string, sub *Command) {
+	fmt.Fprintf(b, "- [`%s`](%s-%s.md)", sub.Name, page, sub.Name)
+	for _, alias := range sub.Aliases {
+		fmt.Fprintf(b, ", `%s`", alias)
+	}
+	if summary := sub.summary(); summary != "" {
+		b.WriteString(": " + summary)
+	}
+	b.WriteString("\n")
+}
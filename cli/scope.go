@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// scope is a level of the command tree being run, stored in the context.
+type scope struct {
+	parent  *scope
+	command *Command
+	flags   *flag.FlagSet
+	args    map[string]flag.Value // values of the positional arguments, once parsed
+}
+
+type ctxScope struct{}
+
+func scopeFrom(ctx context.Context) *scope {
+	s, _ := ctx.Value(ctxScope{}).(*scope)
+	return s
+}
+
+// find returns the nearest scope defining the named flag, or nil if there is none.
+func (s *scope) find(name string) *scope {
+	for ; s != nil; s = s.parent {
+		if s.flags.Lookup(name) != nil {
+			return s
+		}
+	}
+	return nil
+}
+
+// chain returns the scopes from the root down to s.
+func (s *scope) chain() []*scope {
+	chain := []*scope{}
+	for ; s != nil; s = s.parent {
+		chain = append(chain, s)
+	}
+	slices.Reverse(chain)
+	return chain
+}
+
+// path returns the names of the commands from the root down to s.
+func (s *scope) path() string {
+	names := []string{}
+	for _, s := range s.chain() {
+		names = append(names, s.command.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+// lookupFlag returns the named [flag.Flag] stored in the context, or nil if there is none.
+func lookupFlag(ctx context.Context, name string) *flag.Flag {
+	s := scopeFrom(ctx).find(name)
+	if s == nil {
+		return nil
+	}
+	return s.flags.Lookup(name)
+}
+
+// Scope returns a copy of ctx in which flag lookups are restricted to the command
+// found by following the subcommand names of path from the root command, and to its parents.
+// An empty path designates the root command.
+//
+// It allows reaching flags shadowed by subcommands sharing the same name:
+//
+//	cli.Get(ctx, "config")            // the flag of the running command
+//	cli.Get(cli.Scope(ctx), "config") // the flag of the root command
+//
+// If the path does not match the commands being run, no flag will be found.
+func Scope(ctx context.Context, path ...string) context.Context {
+	chain := scopeFrom(ctx).chain()
+
+	var s *scope
+	if len(path) < len(chain) {
+		s = chain[len(path)]
+		for i, name := range path {
+			if chain[i+1].command.Name != name {
+				s = nil
+				break
+			}
+		}
+	}
+
+	return context.WithValue(ctx, ctxScope{}, s)
+}
+
+// Validate checks the command tree for definition errors, such as flags of
+// subcommands shadowing flags of their parents, constraints on undefined flags,
+// subcommands sharing names or aliases, or misordered [Command.Args].
+// It is typically called from tests.
+func (c *Command) Validate() error {
+	return c.validate(nil)
+}
+
+func (c *Command) validate(parent *scope) error {
+	s := &scope{parent: parent, command: c, flags: c.flagSet()}
+
+	errs := []error{c.validateArgs(s.path()), c.validateFlags(s.path(), s.flags)}
+	s.flags.VisitAll(func(f *flag.Flag) {
+		if p := parent.find(f.Name); p != nil {
+			errs = append(errs, fmt.Errorf("flag -%s of command %q shadows flag of command %q", f.Name, s.path(), p.path()))
+		}
+	})
+
+	seen := map[string]*Command{}
+	for _, sub := range c.subcommands() {
+		for _, name := range append([]string{sub.Name}, sub.Aliases...) {
+			if other, ok := seen[name]; ok && other != sub {
+				errs = append(errs, fmt.Errorf("subcommand name %q of command %q is used more than once", name, s.path()))
+			}
+			seen[name] = sub
+		}
+	}
+
+	for _, sub := range c.Subcommands {
+		errs = append(errs, sub.validate(s))
+	}
+
+	return errors.Join(errs...)
+}
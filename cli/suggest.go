@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// UnknownCommandError is returned when an argument does not match any subcommand.
+type UnknownCommandError struct {
+	Name        string
+	Suggestions []string
+}
+
+func (e *UnknownCommandError) Error() string {
+	return fmt.Sprintf("unknown command %q", e.Name) + didYouMean(e.Suggestions, `"`, `"`)
+}
+
+// UnknownFlagError is returned when a flag argument is not defined in the [flag.FlagSet].
+type UnknownFlagError struct {
+	Name        string
+	Suggestions []string
+}
+
+func (e *UnknownFlagError) Error() string {
+	return "flag provided but not defined: -" + e.Name + didYouMean(e.Suggestions, "-", "")
+}
+
+func didYouMean(suggestions []string, before, after string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return ", did you mean " + before + strings.Join(suggestions, after+" or "+before) + after + "?"
+}
+
+// unknownCommand returns an [UnknownCommandError] suggesting subcommands of c close to name.
+func (c *Command) unknownCommand(name string) error {
+	names := []string{}
+	for _, sub := range c.visibleSubcommands() {
+		names = append(names, sub.Name)
+		names = append(names, sub.Aliases...)
+	}
+	return &UnknownCommandError{name, suggest(name, names)}
+}
+
+// unknownFlag converts the error returned by [flag.FlagSet.Parse] for undefined flags
+// into an [UnknownFlagError] suggesting flags close to the undefined one.
+// Other errors are returned as is.
+func unknownFlag(fs *flag.FlagSet, err error) error {
+	msg, ok := strings.CutPrefix(err.Error(), "flag provided but not defined: -")
+	if !ok || errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+
+	names := []string{}
+	fs.VisitAll(func(f *flag.Flag) { names = append(names, f.Name) })
+	return &UnknownFlagError{msg, suggest(msg, names)}
+}
+
+// suggest returns the candidates close to name, either prefixed by name or
+// within a small edit distance, the closest ones first. An empty name has no suggestions.
+func suggest(name string, candidates []string) []string {
+	if name == "" {
+		return nil
+	}
+
+	maxDistance := max(1, min(2, len(name)/3)) //nolint: mnd // empirical threshold
+
+	type suggestion struct {
+		name     string
+		distance int
+	}
+	suggestions := []suggestion{}
+	for _, candidate := range candidates {
+		d := levenshtein(name, candidate)
+		if d <= maxDistance || strings.HasPrefix(candidate, name) {
+			suggestions = append(suggestions, suggestion{candidate, d})
+		}
+	}
+	slices.SortStableFunc(suggestions, func(a, b suggestion) int { return a.distance - b.distance })
+
+	var names []string
+	for _, s := range suggestions {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := range ra {
+		prev := row[0]
+		row[0] = i + 1
+		for j := range rb {
+			cost := 1
+			if ra[i] == rb[j] {
+				cost = 0
+			}
+			prev, row[j+1] = row[j+1], min(row[j+1]+1, row[j]+1, prev+cost)
+		}
+	}
+	return row[len(rb)]
+}
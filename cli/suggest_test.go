@@ -0,0 +1,106 @@
+package cli_test
+
+import (
+	"context"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/cli"
+)
+
+func TestSuggestions(t *testing.T) {
+	c := cli.Command{
+		Name: "foo",
+		Help: true,
+		Flags: func(fs *flag.FlagSet) {
+			fs.SetOutput(io.Discard)
+			fs.Bool("verbose", false, "a bool flag")
+			fs.Bool("version", false, "a bool flag")
+			fs.Int("port", 8080, "an int flag")
+		},
+		Subcommands: []*cli.Command{
+			{Name: "serve"},
+			{Name: "server"},
+			{Name: "status"},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		args        []string
+		target      any
+		message     string
+		suggestions []string
+	}{
+		{
+			name:        "command typo",
+			args:        []string{"serv"},
+			target:      new(*cli.UnknownCommandError),
+			message:     `unknown command "serv", did you mean "serve" or "server"?`,
+			suggestions: []string{"serve", "server"},
+		},
+		{
+			name:        "command prefix",
+			args:        []string{"stat"},
+			target:      new(*cli.UnknownCommandError),
+			message:     `unknown command "stat", did you mean "status"?`,
+			suggestions: []string{"status"},
+		},
+		{
+			name:    "command without suggestion",
+			args:    []string{"deploy"},
+			target:  new(*cli.UnknownCommandError),
+			message: `unknown command "deploy"`,
+		},
+		{
+			name:    "empty command",
+			args:    []string{""},
+			target:  new(*cli.UnknownCommandError),
+			message: `unknown command ""`,
+		},
+		{
+			name:        "help command typo",
+			args:        []string{"help", "statsu"},
+			target:      new(*cli.UnknownCommandError),
+			message:     `unknown command "statsu", did you mean "status"?`,
+			suggestions: []string{"status"},
+		},
+		{
+			name:        "flag typo",
+			args:        []string{"-vrebose"},
+			target:      new(*cli.UnknownFlagError),
+			message:     "flag provided but not defined: -vrebose, did you mean -verbose?",
+			suggestions: []string{"verbose"},
+		},
+		{
+			name:        "flag prefix",
+			args:        []string{"-ver"},
+			target:      new(*cli.UnknownFlagError),
+			message:     "flag provided but not defined: -ver, did you mean -verbose or -version?",
+			suggestions: []string{"verbose", "version"},
+		},
+		{
+			name:    "flag without suggestion",
+			args:    []string{"-quiet"},
+			target:  new(*cli.UnknownFlagError),
+			message: "flag provided but not defined: -quiet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := c.Run(context.Background(), tc.args)
+			require.EqualError(t, err, tc.message)
+			require.ErrorAs(t, err, tc.target)
+			switch err := err.(type) { //nolint: errorlint // checked above
+			case *cli.UnknownCommandError:
+				require.Equal(t, tc.suggestions, err.Suggestions)
+			case *cli.UnknownFlagError:
+				require.Equal(t, tc.suggestions, err.Suggestions)
+			}
+		})
+	}
+}
@@ -40,9 +40,9 @@ func main() {
 				},
 				FlagsRequired: []string{"foo"},
 				Func: func(ctx context.Context, args []string) error {
-					fmt.Println("val", cli.Get(ctx, "val").(int))
-					fmt.Println("dur", cli.Get(ctx, "dur").(time.Duration))
-					fmt.Println("foo", cli.Get(ctx, "foo").(string))
+					fmt.Println("val", cli.MustGet[int](ctx, "val"))
+					fmt.Println("dur", cli.MustGet[time.Duration](ctx, "dur"))
+					fmt.Println("foo", cli.MustGet[string](ctx, "foo"))
 					fmt.Println("arguments", args)
 					return nil
 				},
@@ -54,7 +54,7 @@ func main() {
 					fs.Duration("timeout", 10*time.Second, "wait up to this duration")
 				},
 				Func: func(ctx context.Context, _ []string) error {
-					ctx, cancel := context.WithTimeout(ctx, cli.Get(ctx, "timeout").(time.Duration))
+					ctx, cancel := context.WithTimeout(ctx, cli.MustGet[time.Duration](ctx, "timeout"))
 					defer cancel()
 					fmt.Println("waiting...")
 					<-ctx.Done()
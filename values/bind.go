@@ -0,0 +1,178 @@
+package values
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Bind registers a flag for every exported field of the struct pointed to by ptr.
+// The flags store their values in the fields, whose current values act as defaults.
+//
+// Fields are configured by the following struct tags:
+//
+//   - flag: name of the flag, "-" to skip the field, defaults to the field name in kebab-case
+//   - usage: usage string of the flag
+//   - env: name of the environment variable setting the flag instead of the one derived from
+//     the flag name, requires an [EnvRegisterer]
+//   - sep: separator of slice-style values, slices are list-style values if not set
+//   - layout: layout of [time.Time] values, defaults to [time.RFC3339]
+//
+// Supported field types are those of [Basic] values, [time.Duration], [time.Time],
+// [netip.Addr], [netip.AddrPort], [netip.Prefix], [*mail.Address] and [*url.URL],
+// slices of any of those, and types whose pointer implements [flag.Value].
+// Nil pointer fields are supported.
+//
+// Fields of other struct types are bound recursively, their flag names being
+// prefixed with the name of the field and a dot. Embedded structs without a flag
+// tag are bound without prefix.
+//
+// Unsupported fields, and env tags not honored by the registerer, are reported as errors,
+// joined with [errors.Join]. The flags of env tagged fields are registered nonetheless.
+func (f RegistererFunc) Bind(ptr any) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot bind %T, expected a pointer to a struct", ptr)
+	}
+	return f.bind(v.Elem(), "")
+}
+
+func (f RegistererFunc) bind(v reflect.Value, prefix string) error {
+	var errs []error
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		name, tagged := field.Tag.Lookup("flag")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if !tagged {
+			name = kebabCase(field.Name)
+		}
+
+		p := v.Field(i).Addr().Interface()
+		value := bindValue(p, field.Tag.Get("sep"), field.Tag.Get("layout"))
+		switch {
+		case value != nil:
+			errs = append(errs, f.bindField(value, prefix+name, field.Tag))
+		case field.Type.Kind() == reflect.Struct && field.Anonymous && !tagged:
+			errs = append(errs, f.bind(v.Field(i), prefix))
+		case field.Type.Kind() == reflect.Struct:
+			errs = append(errs, f.bind(v.Field(i), prefix+name+"."))
+		default:
+			errs = append(errs, fmt.Errorf("flag -%s: unsupported type %s", prefix+name, field.Type))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// bindField registers value as the named flag, as configured by the struct tags.
+// It fails if the registerer ignores the env tag, i.e. never asks the value its environment variable.
+func (f RegistererFunc) bindField(value flag.Value, name string, tag reflect.StructTag) error {
+	env := tag.Get("env")
+	if env == "" {
+		f(value, name, tag.Get("usage"))
+		return nil
+	}
+
+	named := &envNamed{wrapper: wrapper{value}, env: env}
+	f(named, name, tag.Get("usage"))
+	if !named.named {
+		return fmt.Errorf("flag -%s: env tag %q requires an EnvRegisterer", name, env)
+	}
+	return nil
+}
+
+// envNamed implements [flag.Value] wrapping another one and naming the environment variable
+// setting it, as given by the env tag of its field. It records whether the name was asked for.
+type envNamed struct {
+	wrapper
+	env   string
+	named bool
+}
+
+func (v *envNamed) envName() string {
+	v.named = true
+	return v.env
+}
+
+// bindValue returns a [flag.Value] storing its values in p, or nil if the type of p is not supported.
+func bindValue(p any, sep, layout string) flag.Value {
+	if value, ok := p.(flag.Value); ok {
+		return value
+	}
+
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	for _, bind := range []func(p any, sep string) flag.Value{
+		bindBasic[[]byte], bindBasic[bool], bindBasic[string],
+		bindBasic[complex64], bindBasic[complex128],
+		bindBasic[int], bindBasic[int8], bindBasic[int16], bindBasic[int32], bindBasic[int64],
+		bindBasic[uint], bindBasic[uint8], bindBasic[uint16], bindBasic[uint32], bindBasic[uint64],
+		bindBasic[float32], bindBasic[float64],
+		bindStringer(netip.ParseAddr), bindStringer(netip.ParseAddrPort), bindStringer(netip.ParsePrefix),
+		bindStringer(mail.ParseAddress), bindStringer(url.Parse),
+		func(p any, sep string) flag.Value { return bindGeneric(p, sep, time.ParseDuration, formatDuration) },
+		func(p any, sep string) flag.Value { return bindGeneric(p, sep, parseTime(layout), formatTime(layout)) },
+	} {
+		if value := bind(p, sep); value != nil {
+			return value
+		}
+	}
+	return nil
+}
+
+// bindGeneric returns a [flag.Value] storing its values in p if it is a *T or a *[]T, or nil otherwise.
+func bindGeneric[T any](p any, sep string, parse func(string) (T, error), format func(T) string) flag.Value {
+	switch p := p.(type) {
+	case *T:
+		return GenericVar(p, parse, format)
+	case *[]T:
+		if sep == "" {
+			return GenericListVar(p, parse, format)
+		}
+		return GenericSliceVar(p, sep, parse, format)
+	default:
+		return nil
+	}
+}
+
+func bindBasic[T basic](p any, sep string) flag.Value {
+	return bindGeneric(p, sep, parseBasic[T], formatBasic[T])
+}
+
+// bindStringer is like bindBasic for [fmt.Stringer] types, formatting nil pointers as empty strings.
+func bindStringer[T fmt.Stringer](parse func(string) (T, error)) func(p any, sep string) flag.Value {
+	format := func(t T) string {
+		if v := reflect.ValueOf(t); v.Kind() == reflect.Pointer && v.IsNil() {
+			return ""
+		}
+		return t.String()
+	}
+	return func(p any, sep string) flag.Value { return bindGeneric(p, sep, parse, format) }
+}
+
+// kebabCase converts a Go identifier to kebab-case, e.g. "HTTPPort" to "http-port".
+func kebabCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			next := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && next) {
+				b.WriteByte('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,183 @@
+package values_test
+
+import (
+	"flag"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/values"
+)
+
+func ExampleRegistererFunc_Bind() {
+	type config struct {
+		Port    int           `usage:"port to listen on"`
+		Timeout time.Duration `usage:"request timeout"`
+		Tags    []string      `flag:"tag" usage:"tags (can be specified multiple times)"`
+		DB      struct {
+			Host string `usage:"database host"`
+		}
+	}
+
+	cfg := config{Port: 8080}
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	if err := values.FlagSetRegisterer(fs).Bind(&cfg); err != nil {
+		panic(err)
+	}
+	fs.Parse([]string{"-tag", "foo", "-db.host", "localhost", "-timeout", "5s"})
+	fmt.Printf("%+v\n", cfg)
+	fs.PrintDefaults()
+
+	// Output:
+	// {Port:8080 Timeout:5s Tags:[foo] DB:{Host:localhost}}
+	//   -db.host value
+	//     	database host
+	//   -port value
+	//     	port to listen on (default 8080)
+	//   -tag value
+	//     	tags (can be specified multiple times)
+	//   -timeout value
+	//     	request timeout (default 0s)
+}
+
+type bindLevel int
+
+func (l *bindLevel) Set(s string) error { *l = bindLevel(len(s)); return nil }
+func (l *bindLevel) String() string     { return fmt.Sprint(int(*l)) }
+
+type BindEmbedded struct {
+	Embedded string
+}
+
+func TestRegistererFunc_Bind(t *testing.T) {
+	t.Setenv("FOO_NAME", "env")
+
+	var cfg struct {
+		BindEmbedded
+		Name     string `env:"FOO_NAME" usage:"a name"`
+		HTTPPort uint16
+		Ratio    float64
+		Verbose  bool
+		Data     []byte
+		Ints     []int      `sep:","`
+		Addr     netip.Addr `flag:"addr"`
+		Bind     netip.AddrPort
+		Prefixes []netip.Prefix `sep:" "`
+		Email    *mail.Address
+		URLs     []*url.URL `flag:"url"`
+		Since    time.Time  `layout:"2006-01-02"`
+		Level    bindLevel
+		Skipped  string `flag:"-"`
+		private  string
+		Nested   struct {
+			Deep struct {
+				Value int `flag:"val"`
+			}
+		} `flag:"n"`
+	}
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	require.NoError(t, values.FlagSetEnvRegisterer(fs, "BIND_").Bind(&cfg))
+	require.NoError(t, fs.Parse([]string{
+		"-embedded", "foo",
+		"-http-port", "8080",
+		"-ratio", "0.5",
+		"-verbose=true",
+		"-data", "bytes",
+		"-ints", "1,2,3",
+		"-addr", "10.0.0.1",
+		"-bind", "10.0.0.1:80",
+		"-prefixes", "10.0.0.0/8 192.168.0.0/16",
+		"-email", "foo@example.com",
+		"-url", "http://a", "-url", "http://b",
+		"-since", "2024-01-02",
+		"-level", "abc",
+		"-n.deep.val", "42",
+	}))
+
+	require.Equal(t, "foo", cfg.Embedded)
+	require.Equal(t, "env", cfg.Name)
+	require.Equal(t, uint16(8080), cfg.HTTPPort)
+	require.InDelta(t, 0.5, cfg.Ratio, 0)
+	require.True(t, cfg.Verbose)
+	require.Equal(t, []byte("bytes"), cfg.Data)
+	require.Equal(t, []int{1, 2, 3}, cfg.Ints)
+	require.Equal(t, netip.MustParseAddr("10.0.0.1"), cfg.Addr)
+	require.Equal(t, netip.MustParseAddrPort("10.0.0.1:80"), cfg.Bind)
+	require.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16")}, cfg.Prefixes)
+	require.Equal(t, "<foo@example.com>", cfg.Email.String())
+	require.Len(t, cfg.URLs, 2)
+	require.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), cfg.Since)
+	require.Equal(t, bindLevel(3), cfg.Level)
+	require.Equal(t, 42, cfg.Nested.Deep.Value)
+
+	require.Nil(t, fs.Lookup("skipped"))
+	require.Nil(t, fs.Lookup("private"))
+	require.Equal(t, "a name (env $FOO_NAME)", fs.Lookup("name").Usage)
+}
+
+func TestRegistererFunc_Bind_envRegisterer(t *testing.T) {
+	t.Setenv("FOO_DB_PORT", "5432")
+	t.Setenv("DATABASE_USER", "admin")
+
+	var cfg struct {
+		DB struct {
+			Port int
+			User string `env:"DATABASE_USER" usage:"database user"`
+			Name string `env:"DATABASE_NAME"`
+		}
+	}
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	r := &values.EnvRegisterer{FlagSet: fs, Prefix: "FOO_"}
+	require.NoError(t, values.RegistererFunc(r.Register).Tracked().Bind(&cfg))
+	require.NoError(t, r.Err())
+	require.Equal(t, 5432, cfg.DB.Port)
+	require.Equal(t, "admin", cfg.DB.User)
+	require.Equal(t, "database user (env $DATABASE_USER)", fs.Lookup("db.user").Usage)
+
+	origin, _ := values.OriginOf(fs.Lookup("db.port").Value)
+	require.Equal(t, values.Origin{Source: values.SourceEnv, Key: "FOO_DB_PORT"}, origin)
+	origin, _ = values.OriginOf(fs.Lookup("db.user").Value)
+	require.Equal(t, values.Origin{Source: values.SourceEnv, Key: "DATABASE_USER"}, origin)
+
+	var placed []string
+	fs.Visit(func(f *flag.Flag) { placed = append(placed, f.Name) })
+	require.Equal(t, []string{"db.port", "db.user"}, placed)
+
+	fs = flag.NewFlagSet("", flag.ContinueOnError)
+	require.EqualError(t, values.FlagSetRegisterer(fs).Bind(&cfg),
+		`flag -db.user: env tag "DATABASE_USER" requires an EnvRegisterer`+"\n"+
+			`flag -db.name: env tag "DATABASE_NAME" requires an EnvRegisterer`)
+	require.Equal(t, "database user", fs.Lookup("db.user").Usage)
+}
+
+func TestRegistererFunc_Bind_errors(t *testing.T) {
+	t.Setenv("FOO_BAD", "notint")
+
+	var cfg struct {
+		Bad         int `env:"FOO_BAD"`
+		Unsupported map[string]string
+		Nested      struct {
+			Channel chan int
+		}
+	}
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	r := &values.EnvRegisterer{FlagSet: fs}
+	err := values.RegistererFunc(r.Register).Bind(&cfg)
+	require.EqualError(t, err, `flag -unsupported: unsupported type map[string]string`+"\n"+
+		`flag -nested.channel: unsupported type chan int`)
+	require.EqualError(t, r.Err(), `invalid value "notint" for flag -bad from env $FOO_BAD: `+
+		`strconv.ParseInt: parsing "notint": invalid syntax`)
+
+	require.EqualError(t, values.FlagSetRegisterer(fs).Bind(cfg),
+		"cannot bind struct { Bad int \"env:\\\"FOO_BAD\\\"\"; Unsupported map[string]string; Nested struct { Channel chan int } }, expected a pointer to a struct")
+}
@@ -0,0 +1,165 @@
+package values
+
+import (
+	"errors"
+	"flag"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// byteUnits are the units of byte sizes, from the largest to the smallest.
+// IEC units precede SI ones of the same magnitude, so that they are preferred when formatting.
+var byteUnits = []struct { //nolint: gochecknoglobals // read-only table
+	name string
+	size uint64
+}{
+	{"EiB", 1 << 60}, {"EB", 1e18},
+	{"PiB", 1 << 50}, {"PB", 1e15},
+	{"TiB", 1 << 40}, {"TB", 1e12},
+	{"GiB", 1 << 30}, {"GB", 1e9},
+	{"MiB", 1 << 20}, {"MB", 1e6},
+	{"KiB", 1 << 10}, {"KB", 1e3},
+	{"B", 1},
+}
+
+// byteUnit returns the size of the named unit, case-insensitively.
+// Single letters such as "K" or "M" are IEC units, and an empty name stands for bytes.
+func byteUnit(name string) (uint64, bool) {
+	if name == "" {
+		return 1, true
+	}
+	if len(name) == 1 && name != "b" && name != "B" {
+		name += "iB"
+	}
+	for _, u := range byteUnits {
+		if strings.EqualFold(u.name, name) {
+			return u.size, true
+		}
+	}
+	return 0, false
+}
+
+var errFractionalBytes = errors.New("fractional number of bytes")
+
+// parseByteSize parses a byte count with an optional unit, such as "512", "10KB", "1.5GiB" or "4M".
+func parseByteSize(s string) (uint64, error) {
+	num := strings.TrimRight(s, "KMGTPEBkmgtpebiI ")
+	size, ok := byteUnit(strings.TrimSpace(s[len(num):]))
+	if !ok || num == "" || num[0] == '-' || num[0] == '+' {
+		return 0, &strconv.NumError{Func: "ParseByteSize", Num: s, Err: strconv.ErrSyntax}
+	}
+
+	r, ok := new(big.Rat).SetString(num)
+	if !ok || strings.ContainsAny(num, "/eE") {
+		return 0, &strconv.NumError{Func: "ParseByteSize", Num: s, Err: strconv.ErrSyntax}
+	}
+
+	r.Mul(r, new(big.Rat).SetUint64(size))
+	if !r.IsInt() {
+		return 0, &strconv.NumError{Func: "ParseByteSize", Num: s, Err: errFractionalBytes}
+	}
+	if !r.Num().IsUint64() {
+		return math.MaxUint64, &strconv.NumError{Func: "ParseByteSize", Num: s, Err: strconv.ErrRange}
+	}
+	return r.Num().Uint64(), nil
+}
+
+// formatByteSize formats a byte count exactly with the unit giving the shortest representation,
+// allowing up to three decimals, such as "1.5GiB" or "10KB".
+func formatByteSize(n uint64) string {
+	best := strconv.FormatUint(n, 10) + "B"
+	for _, u := range byteUnits[:len(byteUnits)-1] {
+		if n < u.size {
+			continue
+		}
+		r := new(big.Rat).SetFrac(new(big.Int).SetUint64(n), new(big.Int).SetUint64(u.size))
+		if !new(big.Rat).Mul(r, big.NewRat(1000, 1)).IsInt() { //nolint: mnd // three decimals
+			continue
+		}
+		s := strings.TrimRight(strings.TrimRight(r.FloatString(3), "0"), ".") + u.name //nolint: mnd // three decimals
+		if len(s) < len(best) {
+			best = s
+		}
+	}
+	return best
+}
+
+// ByteSize declares a [flag.Value] for byte counts with optional SI or IEC units.
+// SI units (KB, MB, GB, ...) are powers of 1000, while IEC units (KiB, MiB, GiB, ...)
+// and single letters (K, M, G, ...) are powers of 1024. Units are case-insensitive
+// and decimal numbers are accepted as long as they amount to a whole number of bytes.
+// The actual value type is uint64.
+func ByteSize() flag.Value {
+	return Generic(parseByteSize, formatByteSize)
+}
+
+// ByteSizeVar is like [ByteSize] but stores the value in p.
+func ByteSizeVar(p *uint64) flag.Value {
+	return GenericVar(p, parseByteSize, formatByteSize)
+}
+
+// ByteSizeList is like [ByteSize] but the actual value type is []uint64, see [GenericList].
+func ByteSizeList() flag.Value {
+	return GenericList(parseByteSize, formatByteSize)
+}
+
+// ByteSizeListVar is like [ByteSizeList] but stores the values in p.
+func ByteSizeListVar(p *[]uint64) flag.Value {
+	return GenericListVar(p, parseByteSize, formatByteSize)
+}
+
+// ByteSizeSlice is like [ByteSize] but the actual value type is []uint64, see [GenericSlice].
+func ByteSizeSlice(sep string) flag.Value {
+	return GenericSlice(sep, parseByteSize, formatByteSize)
+}
+
+// ByteSizeSliceVar is like [ByteSizeSlice] but stores the values in p.
+func ByteSizeSliceVar(p *[]uint64, sep string) flag.Value {
+	return GenericSliceVar(p, sep, parseByteSize, formatByteSize)
+}
+
+// ByteSize defines a byte size flag with specified name, default value, and usage string, see [ByteSize].
+// The return value is the address of a uint64 variable that stores the value of the flag.
+func (f RegistererFunc) ByteSize(name string, value uint64, usage string) *uint64 {
+	f(ByteSizeVar(&value), name, usage)
+	return &value
+}
+
+// ByteSizeVar defines a byte size flag with specified name, default value, and usage string, see [ByteSize].
+// The argument p points to a uint64 variable in which to store the value of the flag.
+func (f RegistererFunc) ByteSizeVar(p *uint64, name string, value uint64, usage string) {
+	*p = value
+	f(ByteSizeVar(p), name, usage)
+}
+
+// ByteSizeList defines a list-style byte size flag with specified name, default value, and usage string.
+// The return value is the address of a uint64 slice that stores the values of the flag.
+func (f RegistererFunc) ByteSizeList(name string, value []uint64, usage string) *[]uint64 {
+	f(ByteSizeListVar(&value), name, usage)
+	return &value
+}
+
+// ByteSizeListVar defines a list-style byte size flag with specified name, default value, and usage string.
+// The argument p points to a uint64 slice variable in which to store the value of the flag.
+func (f RegistererFunc) ByteSizeListVar(p *[]uint64, name string, value []uint64, usage string) {
+	*p = value
+	f(ByteSizeListVar(p), name, usage)
+}
+
+// ByteSizeSlice defines a slice-style byte size flag with specified name, default value, and usage string.
+// The input strings are split around sep before parsing.
+// The return value is the address of a uint64 slice that stores the values of the flag.
+func (f RegistererFunc) ByteSizeSlice(name string, value []uint64, sep string, usage string) *[]uint64 {
+	f(ByteSizeSliceVar(&value, sep), name, usage)
+	return &value
+}
+
+// ByteSizeSliceVar defines a slice-style byte size flag with specified name, default value, and usage string.
+// The input strings are split around sep before parsing.
+// The argument p points to a uint64 slice variable in which to store the value of the flag.
+func (f RegistererFunc) ByteSizeSliceVar(p *[]uint64, name string, value []uint64, sep string, usage string) {
+	*p = value
+	f(ByteSizeSliceVar(p, sep), name, usage)
+}
@@ -0,0 +1,113 @@
+package values_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/values"
+)
+
+func ExampleByteSize() {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	reg := values.FlagSetRegisterer(fs)
+	buffer := reg.ByteSize("buffer", 64<<10, "buffer size")
+	quota := reg.ByteSize("quota", 10e9, "disk quota")
+	fs.Parse([]string{"-buffer", "1.5MiB"})
+	fmt.Println(*buffer, *quota)
+	fs.PrintDefaults()
+
+	// Output:
+	// 1572864 10000000000
+	//   -buffer value
+	//     	buffer size (default 64KiB)
+	//   -quota value
+	//     	disk quota (default 10GB)
+}
+
+func TestByteSize(t *testing.T) {
+	testCases := []struct {
+		input  string
+		expect uint64
+		str    string
+	}{
+		{"0", 0, "0B"},
+		{"512", 512, "512B"},
+		{"512B", 512, "512B"},
+		{"10KB", 10000, "10KB"},
+		{"10kb", 10000, "10KB"},
+		{"10 KiB", 10240, "10KiB"},
+		{"4M", 4 << 20, "4MiB"},
+		{"4m", 4 << 20, "4MiB"},
+		{"1.5GiB", 3 << 29, "1.5GiB"},
+		{"1.5GB", 1.5e9, "1.5GB"},
+		{"0.5K", 512, "512B"},
+		{"1234567", 1234567, "1234567B"},
+		{"1.001KB", 1001, "1001B"},
+		{"1.25MiB", 1310720, "1.25MiB"},
+		{"2T", 2 << 40, "2TiB"},
+		{"3PB", 3e15, "3PB"},
+		{"15EiB", 15 << 60, "15EiB"},
+		{"18446744073709551615", 1<<64 - 1, "18446744073709551615B"},
+		{"16EiB", 0, ""},
+		{"18.446744073709551616EB", 0, ""},
+		{"1.5B", 0, ""},
+		{"1.0001KiB", 0, ""},
+		{"-1", 0, ""},
+		{"+1", 0, ""},
+		{"1e3", 0, ""},
+		{"1/2K", 0, ""},
+		{"KB", 0, ""},
+		{"10XB", 0, ""},
+		{"", 0, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			v := values.ByteSize()
+			err := v.Set(tc.input)
+			if tc.str == "" {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, v.(flag.Getter).Get())
+			require.Equal(t, tc.str, v.String())
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		require.ErrorIs(t, values.ByteSize().Set("16EiB"), strconv.ErrRange)
+		require.ErrorIs(t, values.ByteSize().Set("10XB"), strconv.ErrSyntax)
+		require.EqualError(t, values.ByteSize().Set("1.5B"),
+			`strconv.ParseByteSize: parsing "1.5B": fractional number of bytes`)
+	})
+}
+
+func TestRegisterer_byteSizes(t *testing.T) {
+	var single uint64
+	var list, slice []uint64
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	reg := values.FlagSetRegisterer(fs)
+	reg.ByteSizeVar(&single, "single", 1, "usage")
+	reg.ByteSizeListVar(&list, "list", nil, "usage")
+	reg.ByteSizeSliceVar(&slice, "slice", []uint64{1 << 10}, ",", "usage")
+	l := reg.ByteSizeList("l", []uint64{1}, "usage")
+	s := reg.ByteSizeSlice("s", nil, ",", "usage")
+	require.NoError(t, fs.Parse([]string{
+		"-single", "1K", "-list", "1K", "-list", "1KB", "-slice", "1M,2M", "-l", "2", "-s", "1,2",
+	}))
+
+	require.Equal(t, uint64(1024), single)
+	require.Equal(t, []uint64{1024, 1000}, list)
+	require.Equal(t, []uint64{1 << 20, 2 << 20}, slice)
+	require.Equal(t, []uint64{1, 2}, *l)
+	require.Equal(t, []uint64{1, 2}, *s)
+	require.Equal(t, "1KiB", fs.Lookup("slice").DefValue)
+	require.Equal(t, "1MiB,2MiB", fs.Lookup("slice").Value.String())
+}
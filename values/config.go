@@ -0,0 +1,216 @@
+package values
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// configEntry is a key/value pair read from a configuration file.
+type configEntry struct {
+	key   string
+	value string
+	line  int // line number of the entry, if known
+}
+
+// ConfigError records an entry of a configuration file that could not be applied to a [flag.FlagSet].
+type ConfigError struct {
+	Name string // name of the configuration file
+	Line int    // line number of the entry, or zero if unknown
+	Key  string // key of the entry, which is also the name of the flag
+	Err  error
+}
+
+// ErrUnknownKey is wrapped by [ConfigError] when the key does not match any flag.
+var ErrUnknownKey = errors.New("unknown key")
+
+func (e *ConfigError) Error() string {
+	pos := e.Name
+	if e.Line > 0 {
+		pos += ":" + strconv.Itoa(e.Line)
+	}
+	if errors.Is(e.Err, ErrUnknownKey) {
+		return fmt.Sprintf("%s: %v %q", pos, e.Err, e.Key)
+	}
+	return fmt.Sprintf("%s: flag -%s: %v", pos, e.Key, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// LoadConfigFile reads the configuration file at path and sets the flags of fs accordingly.
+// Files with a ".json" extension are read by [LoadJSON], any other file by [LoadINI].
+func LoadConfigFile(fs *flag.FlagSet, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return LoadJSON(fs, f, path)
+	}
+	return LoadINI(fs, f, path)
+}
+
+// LoadJSON reads a JSON object from r and sets the flags of fs accordingly.
+// The name of the configuration is used in errors and recorded as the [Origin] of tracked values.
+//
+// Nested objects map to dotted flag names, so that {"db": {"host": "localhost"}}
+// sets the flag "db.host". Arrays set their flag once per element, which suits
+// list-style values. Null values are ignored.
+//
+// See [LoadINI] for the precedence rules and error reporting.
+func LoadJSON(fs *flag.FlagSet, r io.Reader, name string) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	entries, err := flattenJSON(nil, "", obj)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return applyConfig(fs, name, entries)
+}
+
+// flattenJSON appends to entries the key/value pairs of obj, prefixing the keys with prefix.
+func flattenJSON(entries []configEntry, prefix string, obj map[string]any) ([]configEntry, error) {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var err error
+		switch val := obj[key].(type) {
+		case map[string]any:
+			entries, err = flattenJSON(entries, prefix+key+".", val)
+		case []any:
+			for _, elem := range val {
+				entries, err = appendJSON(entries, prefix+key, elem)
+				if err != nil {
+					break
+				}
+			}
+		default:
+			entries, err = appendJSON(entries, prefix+key, val)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// appendJSON appends to entries the scalar JSON value val.
+func appendJSON(entries []configEntry, key string, val any) ([]configEntry, error) {
+	switch val := val.(type) {
+	case nil:
+		return entries, nil
+	case string:
+		return append(entries, configEntry{key: key, value: val}), nil
+	case json.Number:
+		return append(entries, configEntry{key: key, value: val.String()}), nil
+	case bool:
+		return append(entries, configEntry{key: key, value: strconv.FormatBool(val)}), nil
+	default:
+		return nil, fmt.Errorf("key %q: unsupported value %v", key, val)
+	}
+}
+
+// LoadINI reads an INI configuration from r and sets the flags of fs accordingly.
+// The name of the configuration is used in errors and recorded as the [Origin] of tracked values.
+//
+// Each line holds either a key=value pair, a [section] header, a comment starting
+// with '#' or ';', or nothing. Keys following a section header are prefixed with
+// the section name and a dot, so that "host" in section "db" sets the flag "db.host".
+// Double-quoted values are unquoted with [strconv.Unquote]. Keys may be repeated
+// to set list-style values multiple times.
+//
+// Flags already set, by [FlagSetEnvRegisterer] or a previous call to [flag.FlagSet.Parse],
+// are left untouched: loading a configuration between flags registration and parsing
+// of the command line gives the precedence default < file < env < command line.
+//
+// Keys not matching any flag and values failing to set their flag are reported
+// as [ConfigError], joined with [errors.Join]. Valid entries are applied regardless.
+func LoadINI(fs *flag.FlagSet, r io.Reader, name string) error {
+	entries, err := parseINI(r)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return applyConfig(fs, name, entries)
+}
+
+// parseINI reads the key/value pairs of an INI configuration.
+func parseINI(r io.Reader) ([]configEntry, error) {
+	var (
+		entries []configEntry
+		section string
+		scanner = bufio.NewScanner(r)
+	)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "", line[0] == '#', line[0] == ';':
+			continue
+		case line[0] == '[' && line[len(line)-1] == ']':
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section != "" {
+				section += "."
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key=value, got %q", n, line)
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) > 1 && value[0] == '"' {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			value = unquoted
+		}
+		entries = append(entries, configEntry{section + strings.TrimSpace(key), value, n})
+	}
+	return entries, scanner.Err()
+}
+
+// applyConfig sets the flags of fs with entries, skipping flags already set.
+func applyConfig(fs *flag.FlagSet, name string, entries []configEntry) error {
+	placed := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { placed[f.Name] = true })
+
+	var errs []error
+	for _, e := range entries {
+		var err error
+		switch {
+		case fs.Lookup(e.key) == nil:
+			err = ErrUnknownKey
+		case placed[e.key]:
+			continue
+		default:
+			err = setFrom(fs, e.key, e.value, Origin{Source: SourceFile, Key: name})
+		}
+		if err != nil {
+			errs = append(errs, &ConfigError{Name: name, Line: e.line, Key: e.key, Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
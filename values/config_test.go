@@ -0,0 +1,149 @@
+package values_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/values"
+)
+
+func ExampleLoadINI() {
+	os.Setenv("FOO_DB_PORT", "8081")
+	defer os.Unsetenv("FOO_DB_PORT")
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	reg := values.FlagSetEnvRegisterer(fs, "FOO_")
+	host := reg.String("db.host", "localhost", "database host")
+	port := reg.Int("db.port", 5432, "database port")
+	verbose := reg.Bool("verbose", false, "enable verbose output")
+
+	err := values.LoadINI(fs, strings.NewReader("verbose = true\n[db]\nhost = example.com\nport = 8080\n"), "foo.ini")
+	if err != nil {
+		panic(err)
+	}
+	fs.Parse([]string{"-verbose=false"})
+	fmt.Println(*host, *port, *verbose)
+
+	// Output:
+	// example.com 8081 false
+}
+
+func configFlagSet() (*flag.FlagSet, *int, *[]string) {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	reg := values.FlagSetRegisterer(fs).Tracked()
+	reg.String("name", "", "a string")
+	reg.Bool("db.tls", false, "a bool")
+	return fs, reg.Int("db.port", 0, "an int"), reg.StringList("tag", nil, "a list")
+}
+
+func TestLoadINI(t *testing.T) {
+	fs, port, tags := configFlagSet()
+	require.NoError(t, fs.Parse([]string{"-name", "cli"}))
+
+	err := values.LoadINI(fs, strings.NewReader(`
+# comment
+; comment
+name = ini
+tag = foo
+tag = "bar baz"
+
+[db]
+port = 5432
+tls = true
+`), "test.ini")
+	require.NoError(t, err)
+	require.Equal(t, "cli", fs.Lookup("name").Value.String())
+	require.Equal(t, "true", fs.Lookup("db.tls").Value.String())
+	require.Equal(t, 5432, *port)
+	require.Equal(t, []string{"foo", "bar baz"}, *tags)
+
+	origin, _ := values.OriginOf(fs.Lookup("db.port").Value)
+	require.Equal(t, values.Origin{Source: values.SourceFile, Key: "test.ini"}, origin)
+	origin, _ = values.OriginOf(fs.Lookup("name").Value)
+	require.Equal(t, values.Origin{Source: values.SourceCommandLine}, origin)
+}
+
+func TestLoadINI_errors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{"unknown key", "port = 1\n[db]\nport = 2", `test.ini:1: unknown key "port"`},
+		{"invalid value", "[db]\nport = abc", `test.ini:2: flag -db.port: strconv.ParseInt: parsing "abc": invalid syntax`},
+		{"syntax", "name", `test.ini: line 1: expected key=value, got "name"`},
+		{"quotes", `name = "foo`, `test.ini: line 1: invalid syntax`},
+		{"joined", "foo = 1\nbar = 2", `test.ini:1: unknown key "foo"` + "\n" + `test.ini:2: unknown key "bar"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs, port, _ := configFlagSet()
+			require.EqualError(t, values.LoadINI(fs, strings.NewReader(tc.input), "test.ini"), tc.err)
+			if tc.name == "unknown key" {
+				require.Equal(t, 2, *port, "valid entries are applied regardless")
+			}
+		})
+	}
+
+	t.Run("error is", func(t *testing.T) {
+		fs, _, _ := configFlagSet()
+		require.ErrorIs(t, values.LoadINI(fs, strings.NewReader("foo = 1"), "test.ini"), values.ErrUnknownKey)
+	})
+}
+
+func TestLoadJSON(t *testing.T) {
+	fs, port, tags := configFlagSet()
+	err := values.LoadJSON(fs, strings.NewReader(`{
+		"name": "json",
+		"tag": ["foo", "bar"],
+		"db": {"port": 5432, "tls": true},
+		"ignored": null
+	}`), "test.json")
+	require.NoError(t, err)
+	require.Equal(t, "json", fs.Lookup("name").Value.String())
+	require.Equal(t, "true", fs.Lookup("db.tls").Value.String())
+	require.Equal(t, 5432, *port)
+	require.Equal(t, []string{"foo", "bar"}, *tags)
+
+	testCases := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{"unknown key", `{"db": {"host": "localhost"}}`, `test.json: unknown key "db.host"`},
+		{"invalid value", `{"db": {"port": 1.5}}`, `test.json: flag -db.port: strconv.ParseInt: parsing "1.5": invalid syntax`},
+		{"nested array", `{"tag": [["foo"]]}`, `test.json: key "tag": unsupported value [foo]`},
+		{"not an object", `["foo"]`, `test.json: json: cannot unmarshal array into Go value of type map[string]interface {}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs, _, _ := configFlagSet()
+			require.EqualError(t, values.LoadJSON(fs, strings.NewReader(tc.input), "test.json"), tc.err)
+		})
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"db": {"port": 1}}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.ini"), []byte("[db]\nport = 2\n"), 0o600))
+
+	fs, port, _ := configFlagSet()
+	require.NoError(t, values.LoadConfigFile(fs, filepath.Join(dir, "config.json")))
+	require.Equal(t, 1, *port)
+
+	fs, port, _ = configFlagSet()
+	require.NoError(t, values.LoadConfigFile(fs, filepath.Join(dir, "config.ini")))
+	require.Equal(t, 2, *port)
+
+	fs, _, _ = configFlagSet()
+	require.ErrorIs(t, values.LoadConfigFile(fs, filepath.Join(dir, "missing.ini")), os.ErrNotExist)
+}
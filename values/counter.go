@@ -0,0 +1,76 @@
+package values
+
+import (
+	"errors"
+	"flag"
+	"strconv"
+)
+
+// counter implements [flag.Value] counting the occurrences of a boolean-like flag.
+type counter struct {
+	value *int
+}
+
+var errNegativeCount = errors.New("negative count")
+
+func (v *counter) Set(s string) error {
+	if b, err := strconv.ParseBool(s); err == nil {
+		if b {
+			*v.value++
+		} else {
+			*v.value = 0
+		}
+		return nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errNegativeCount
+	}
+	*v.value = n
+	return nil
+}
+
+func (v *counter) String() string {
+	if v.value == nil {
+		return "0"
+	}
+	return strconv.Itoa(*v.value)
+}
+
+func (v *counter) Get() any {
+	return *v.value
+}
+
+func (v *counter) IsBoolFlag() bool { return true }
+
+// Counter declares a [flag.Value] counting the occurrences of its flag, such as -v -v -v.
+// It is a boolean flag for the [flag] package: each occurrence without value increments
+// the count, as do boolean values accepted by [strconv.ParseBool] such as -v=true or -v=1,
+// while false ones such as -v=false reset it, and other integers such as -v=3 set it.
+// The actual value type is int.
+func Counter() flag.Value {
+	return &counter{new(int)}
+}
+
+// CounterVar is like [Counter] but stores the value in p.
+func CounterVar(p *int) flag.Value {
+	return &counter{p}
+}
+
+// Count defines a counter flag with specified name, default value, and usage string, see [Counter].
+// The return value is the address of an int variable that stores the value of the flag.
+func (f RegistererFunc) Count(name string, value int, usage string) *int {
+	f(CounterVar(&value), name, usage)
+	return &value
+}
+
+// CountVar defines a counter flag with specified name, default value, and usage string, see [Counter].
+// The argument p points to an int variable in which to store the value of the flag.
+func (f RegistererFunc) CountVar(p *int, name string, value int, usage string) {
+	*p = value
+	f(CounterVar(p), name, usage)
+}
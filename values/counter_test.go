@@ -0,0 +1,70 @@
+package values_test
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/values"
+)
+
+func ExampleCounter() {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	verbosity := values.FlagSetRegisterer(fs).Count("v", 0, "verbosity (can be repeated)")
+	fs.Parse([]string{"-v", "-v", "-v", "arg"})
+	fmt.Println(*verbosity, fs.Args())
+	fs.PrintDefaults()
+
+	// Output:
+	// 3 [arg]
+	//   -v	verbosity (can be repeated)
+}
+
+func TestCounter(t *testing.T) {
+	testCases := []struct {
+		name   string
+		value  int
+		args   []string
+		expect int
+		err    string
+	}{
+		{"default", 1, nil, 1, ""},
+		{"zero default", 0, nil, 0, ""},
+		{"repeated", 1, []string{"-v", "-v"}, 3, ""},
+		{"explicit", 1, []string{"-v", "-v=5"}, 5, ""},
+		{"explicit then repeated", 1, []string{"-v=5", "-v"}, 6, ""},
+		{"boolean", 0, []string{"-v=T", "-v=1", "-v=true"}, 3, ""},
+		{"reset", 1, []string{"-v", "-v=false"}, 0, ""},
+		{"reset with zero", 1, []string{"-v", "-v=0"}, 0, ""},
+		{"invalid", 1, []string{"-v=foo"}, 0, `invalid boolean value "foo" for -v: strconv.Atoi: parsing "foo": invalid syntax`}, //nolint: golines
+		{"negative", 1, []string{"-v=-1"}, 0, `invalid boolean value "-1" for -v: negative count`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var count int
+			fs := flag.NewFlagSet("", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			values.FlagSetRegisterer(fs).Tracked().CountVar(&count, "v", tc.value, "usage")
+			err := fs.Parse(tc.args)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, count)
+			require.Equal(t, tc.expect, fs.Lookup("v").Value.(flag.Getter).Get())
+		})
+	}
+
+	t.Run("Counter", func(t *testing.T) {
+		v := values.Counter()
+		require.NoError(t, v.Set("true"))
+		require.Equal(t, "1", v.String())
+	})
+}
@@ -0,0 +1,188 @@
+package values
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Dotenv holds the environment variables read from a dotenv file.
+type Dotenv map[string]string
+
+// ReadDotenv reads the dotenv file at path, see [ParseDotenv].
+func ReadDotenv(path string) (Dotenv, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	env, err := ParseDotenv(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return env, nil
+}
+
+// ParseDotenv reads a dotenv file from r.
+//
+// Each line holds either a KEY=value assignment, optionally prefixed with "export",
+// a comment starting with '#', or nothing. Values may be:
+//
+//   - unquoted, up to a comment starting with " #"
+//   - single-quoted, read verbatim
+//   - double-quoted, supporting backslash escapes such as \n, \" or \$
+//
+// Unquoted and double-quoted values interpolate $VAR and ${VAR} references,
+// resolved with the variables read so far, then with the process environment.
+func ParseDotenv(r io.Reader) (Dotenv, error) {
+	env := Dotenv{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(line, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			line = strings.TrimSpace(rest)
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !isEnvName(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=value, got %q", n, line)
+		}
+
+		value, err := env.parseValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		env[key] = value
+	}
+	return env, scanner.Err()
+}
+
+// LookupEnv retrieves the value of the environment variable named by the key,
+// from the process environment first and from the dotenv file otherwise.
+// It has the signature of [os.LookupEnv]. Unlike [EnvRegisterer.Dotenv], values
+// retrieved this way are not told apart from the process environment in [Origin].
+func (d Dotenv) LookupEnv(key string) (string, bool) {
+	if val, ok := os.LookupEnv(key); ok {
+		return val, true
+	}
+	val, ok := d[key]
+	return val, ok
+}
+
+var errUnterminatedQuote = errors.New("unterminated quoted value")
+
+// parseValue returns the value of an assignment.
+func (d Dotenv) parseValue(s string) (string, error) {
+	if s == "" || (s[0] != '\'' && s[0] != '"') {
+		if i := strings.Index(" "+s, " #"); i >= 0 {
+			s = strings.TrimSpace(s[:i])
+		}
+		return d.interpolate(s, false), nil
+	}
+
+	end := closingQuote(s)
+	if end < 0 {
+		return "", errUnterminatedQuote
+	}
+	if rest := strings.TrimSpace(s[end+1:]); rest != "" && rest[0] != '#' {
+		return "", fmt.Errorf("unexpected characters after quoted value: %q", rest)
+	}
+	if s[0] == '\'' {
+		return s[1:end], nil
+	}
+	return d.interpolate(s[1:end], true), nil
+}
+
+// closingQuote returns the index of the quote closing the quoted string s, or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == s[0]:
+			return i
+		case s[i] == '\\' && s[0] == '"':
+			i++
+		}
+	}
+	return -1
+}
+
+// interpolate replaces the $VAR and ${VAR} references of s, and its backslash escapes if escapes is set.
+func (d Dotenv) interpolate(s string, escapes bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && escapes && i+1 < len(s):
+			i++
+			b.WriteString(unescape(s[i]))
+		case s[i] == '$':
+			name, n := envReference(s[i+1:])
+			if n == 0 {
+				b.WriteByte('$')
+				continue
+			}
+			val, ok := d[name]
+			if !ok {
+				val = os.Getenv(name)
+			}
+			b.WriteString(val)
+			i += n
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// envReference returns the variable name referenced at the start of s, which
+// follows a '$', and the number of bytes it spans, or zero if there is none.
+func envReference(s string) (string, int) {
+	if strings.HasPrefix(s, "{") {
+		end := strings.IndexByte(s, '}')
+		if end < 0 || !isEnvName(s[1:end]) {
+			return "", 0
+		}
+		return s[1:end], end + 1
+	}
+
+	n := 0
+	for n < len(s) && isEnvNameByte(s[n], n == 0) {
+		n++
+	}
+	return s[:n], n
+}
+
+func unescape(c byte) string {
+	switch c {
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	default:
+		return string(c)
+	}
+}
+
+// isEnvName reports whether s is a valid environment variable name.
+func isEnvName(s string) bool {
+	for i := range len(s) {
+		if !isEnvNameByte(s[i], i == 0) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isEnvNameByte(c byte, first bool) bool {
+	return c == '_' || ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || (!first && '0' <= c && c <= '9')
+}
@@ -0,0 +1,139 @@
+package values_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/values"
+)
+
+func ExampleDotenv_LookupEnv() {
+	env, err := values.ParseDotenv(strings.NewReader("# local settings\nexport FOO_COUNT=12\n"))
+	if err != nil {
+		panic(err)
+	}
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	r := &values.EnvRegisterer{FlagSet: fs, Prefix: "FOO_", LookupEnv: env.LookupEnv}
+	count := values.RegistererFunc(r.Register).Int("count", 10, "number of items")
+	fmt.Println(*count)
+
+	// Output:
+	// 12
+}
+
+func TestParseDotenv(t *testing.T) {
+	t.Setenv("FOO_HOME", "/home/foo")
+	t.Setenv("FOO_SHADOWED", "process")
+
+	env, err := values.ParseDotenv(strings.NewReader(`
+# comment
+EMPTY=
+COMMENT= # comment
+PLAIN=foo bar # comment
+HASH=foo#bar
+export EXPORTED=foo
+export	TABBED=foo
+exported=foo
+SPACED = foo
+SINGLE='foo $PLAIN \n' # comment
+DOUBLE="foo\t\"$PLAIN\"\n\$PLAIN"
+BRACES=${PLAIN}baz
+PROCESS=$FOO_HOME/.config
+MISSING=a${FOO_MISSING}b
+FOO_SHADOWED=dotenv
+SHADOWING=${FOO_SHADOWED}
+DOLLAR=$ ${ $1
+`))
+	require.NoError(t, err)
+	require.Equal(t, values.Dotenv{
+		"EMPTY":        "",
+		"COMMENT":      "",
+		"PLAIN":        "foo bar",
+		"HASH":         "foo#bar",
+		"EXPORTED":     "foo",
+		"TABBED":       "foo",
+		"exported":     "foo",
+		"SPACED":       "foo",
+		"SINGLE":       `foo $PLAIN \n`,
+		"DOUBLE":       "foo\t\"foo bar\"\n$PLAIN",
+		"BRACES":       "foo barbaz",
+		"PROCESS":      "/home/foo/.config",
+		"MISSING":      "ab",
+		"FOO_SHADOWED": "dotenv",
+		"SHADOWING":    "dotenv",
+		"DOLLAR":       "$ ${ $1",
+	}, env)
+
+	testCases := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{"no assignment", "FOO", `line 1: expected KEY=value, got "FOO"`},
+		{"invalid name", "1FOO=bar", `line 1: expected KEY=value, got "1FOO=bar"`},
+		{"unterminated quote", `FOO="bar`, `line 1: unterminated quoted value`},
+		{"trailing characters", `FOO='bar' baz`, `line 1: unexpected characters after quoted value: "baz"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := values.ParseDotenv(strings.NewReader(tc.input))
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestDotenvLookupEnv(t *testing.T) {
+	t.Setenv("FOO_PROCESS", "process")
+
+	env := values.Dotenv{"FOO_PROCESS": "dotenv", "FOO_DOTENV": "dotenv"}
+	val, ok := env.LookupEnv("FOO_PROCESS")
+	require.True(t, ok)
+	require.Equal(t, "process", val)
+	val, ok = env.LookupEnv("FOO_DOTENV")
+	require.True(t, ok)
+	require.Equal(t, "dotenv", val)
+	_, ok = env.LookupEnv("FOO_MISSING")
+	require.False(t, ok)
+}
+
+func TestEnvRegistererDotenv(t *testing.T) {
+	t.Setenv("FOO_PROCESS", "1")
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	r := &values.EnvRegisterer{
+		FlagSet:    fs,
+		Prefix:     "FOO_",
+		Dotenv:     values.Dotenv{"FOO_PROCESS": "2", "FOO_DOTENV": "3"},
+		DotenvName: "local.env",
+	}
+	reg := values.RegistererFunc(r.Register).Tracked()
+	process, dotenv := reg.Int("process", 0, ""), reg.Int("dotenv", 0, "")
+	require.NoError(t, r.Err())
+	require.Equal(t, 1, *process)
+	require.Equal(t, 3, *dotenv)
+
+	origin, _ := values.OriginOf(fs.Lookup("process").Value)
+	require.Equal(t, "env $FOO_PROCESS", origin.String())
+	origin, _ = values.OriginOf(fs.Lookup("dotenv").Value)
+	require.Equal(t, values.Origin{Source: values.SourceEnv, Key: "FOO_DOTENV", Dotenv: "local.env"}, origin)
+	require.Equal(t, "env $FOO_DOTENV (dotenv local.env)", origin.String())
+}
+
+func TestReadDotenv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("FOO=bar\nBAD\n"), 0o600))
+
+	_, err := values.ReadDotenv(path)
+	require.EqualError(t, err, path+`: line 2: expected KEY=value, got "BAD"`)
+
+	_, err = values.ReadDotenv(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
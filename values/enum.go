@@ -0,0 +1,190 @@
+package values
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// parseEnum returns a parse function accepting only the formatted choices.
+func parseEnum[T any](format func(T) string, choices []T) func(string) (T, error) {
+	return func(s string) (T, error) {
+		for _, c := range choices {
+			if format(c) == s {
+				return c, nil
+			}
+		}
+		var zero T
+		return zero, fmt.Errorf("%q is not one of %s", s, strings.Join(formatChoices(format, choices), ", "))
+	}
+}
+
+func formatChoices[T any](format func(T) string, choices []T) []string {
+	names := make([]string, len(choices))
+	for i, c := range choices {
+		names[i] = format(c)
+	}
+	return names
+}
+
+// enum implements [flag.Value] wrapping another one and completing its choices.
+type enum struct {
+	wrapper
+	sep     string // separator of slice-style values, if any
+	choices []string
+}
+
+// Complete returns the choices matching prefix. For slice-style values, only the
+// part of prefix after the last separator is completed.
+func (v *enum) Complete(prefix string) []string {
+	head := ""
+	if v.sep != "" {
+		if i := strings.LastIndex(prefix, v.sep); i >= 0 {
+			head, prefix = prefix[:i+len(v.sep)], prefix[i+len(v.sep):]
+		}
+	}
+
+	candidates := []string{}
+	for _, c := range v.choices {
+		if strings.HasPrefix(c, prefix) {
+			candidates = append(candidates, head+c)
+		}
+	}
+	return candidates
+}
+
+// Choices returns the formatted choices of the value.
+func (v *enum) Choices() []string { return v.choices }
+
+// newEnum wraps v in an enum completing the formatted choices.
+func newEnum[T any](v flag.Value, sep string, format func(T) string, choices []T) flag.Value {
+	return &enum{wrapper{v}, sep, formatChoices(format, choices)}
+}
+
+// Enum declares a [flag.Value] accepting only the given choices, as formatted by format.
+// The actual value type is T. The value also completes its choices, as expected by
+// the Completer interface of package [github.com/rlibaert/flag/cli], and lists them
+// with its Choices method, see [Usage].
+func Enum[T any](format func(T) string, choices ...T) flag.Value {
+	return newEnum(Generic(parseEnum(format, choices), format), "", format, choices)
+}
+
+// EnumVar is like [Enum] but stores the value in p.
+func EnumVar[T any](p *T, format func(T) string, choices ...T) flag.Value {
+	return newEnum(GenericVar(p, parseEnum(format, choices), format), "", format, choices)
+}
+
+// EnumList is like [Enum] but the actual value type is []T, see [GenericList].
+func EnumList[T any](format func(T) string, choices ...T) flag.Value {
+	return newEnum(GenericList(parseEnum(format, choices), format), "", format, choices)
+}
+
+// EnumListVar is like [EnumList] but stores the values in p.
+func EnumListVar[T any](p *[]T, format func(T) string, choices ...T) flag.Value {
+	return newEnum(GenericListVar(p, parseEnum(format, choices), format), "", format, choices)
+}
+
+// EnumSlice is like [Enum] but the actual value type is []T, see [GenericSlice].
+func EnumSlice[T any](sep string, format func(T) string, choices ...T) flag.Value {
+	return newEnum(GenericSlice(sep, parseEnum(format, choices), format), sep, format, choices)
+}
+
+// EnumSliceVar is like [EnumSlice] but stores the values in p.
+func EnumSliceVar[T any](p *[]T, sep string, format func(T) string, choices ...T) flag.Value {
+	return newEnum(GenericSliceVar(p, sep, parseEnum(format, choices), format), sep, format, choices)
+}
+
+func identity[T any](v T) T { return v }
+
+// Usage returns usage followed by the choices of value, if it lists them with a
+// Choices method like [Enum] values do, to be given along with value to [flag.FlagSet.Var].
+func Usage(value flag.Value, usage string) string {
+	c, ok := value.(interface{ Choices() []string })
+	if !ok || len(c.Choices()) == 0 {
+		return usage
+	}
+	return fmt.Sprintf("%s (one of %s)", usage, strings.Join(c.Choices(), ", "))
+}
+
+// enumDefaults panics if any of the default values of the named flag is not one of choices,
+// much like [flag.FlagSet.Var] does for flags defined twice.
+func enumDefaults(name string, values []string, choices []string) {
+	for _, v := range values {
+		if !slices.Contains(choices, v) {
+			panic(fmt.Sprintf("flag -%s: default value %q is not one of %s", name, v, strings.Join(choices, ", ")))
+		}
+	}
+}
+
+// Enum defines a string flag accepting only the given choices, with specified name, default value,
+// and usage string. The choices are listed in the usage string.
+// The return value is the address of a string variable that stores the value of the flag.
+// It panics if the default value is neither empty, standing for no choice, nor one of the choices.
+func (f RegistererFunc) Enum(name string, value string, choices []string, usage string) *string {
+	if value != "" {
+		enumDefaults(name, []string{value}, choices)
+	}
+	v := EnumVar(&value, identity, choices...)
+	f(v, name, Usage(v, usage))
+	return &value
+}
+
+// EnumVar defines a string flag accepting only the given choices, with specified name, default value,
+// and usage string. The choices are listed in the usage string.
+// The argument p points to a string variable in which to store the value of the flag.
+// It panics if the default value is neither empty, standing for no choice, nor one of the choices.
+func (f RegistererFunc) EnumVar(p *string, name string, value string, choices []string, usage string) {
+	if value != "" {
+		enumDefaults(name, []string{value}, choices)
+	}
+	*p = value
+	v := EnumVar(p, identity, choices...)
+	f(v, name, Usage(v, usage))
+}
+
+// EnumList defines a list-style string flag accepting only the given choices, with specified name,
+// default value, and usage string. The choices are listed in the usage string.
+// The return value is the address of a string slice that stores the values of the flag.
+// It panics if any of the default values is not one of the choices.
+func (f RegistererFunc) EnumList(name string, value []string, choices []string, usage string) *[]string {
+	enumDefaults(name, value, choices)
+	v := EnumListVar(&value, identity, choices...)
+	f(v, name, Usage(v, usage))
+	return &value
+}
+
+// EnumListVar defines a list-style string flag accepting only the given choices, with specified name,
+// default value, and usage string. The choices are listed in the usage string.
+// The argument p points to a string slice variable in which to store the values of the flag.
+// It panics if any of the default values is not one of the choices.
+func (f RegistererFunc) EnumListVar(p *[]string, name string, value []string, choices []string, usage string) {
+	enumDefaults(name, value, choices)
+	*p = value
+	v := EnumListVar(p, identity, choices...)
+	f(v, name, Usage(v, usage))
+}
+
+// EnumSlice defines a slice-style string flag accepting only the given choices, with specified name,
+// default value, and usage string. The choices are listed in the usage string.
+// The input strings are split around sep before parsing.
+// The return value is the address of a string slice that stores the values of the flag.
+// It panics if any of the default values is not one of the choices.
+func (f RegistererFunc) EnumSlice(name string, value []string, sep string, choices []string, usage string) *[]string {
+	enumDefaults(name, value, choices)
+	v := EnumSliceVar(&value, sep, identity, choices...)
+	f(v, name, Usage(v, usage))
+	return &value
+}
+
+// EnumSliceVar defines a slice-style string flag accepting only the given choices, with specified name,
+// default value, and usage string. The choices are listed in the usage string.
+// The input strings are split around sep before parsing.
+// The argument p points to a string slice variable in which to store the values of the flag.
+// It panics if any of the default values is not one of the choices.
+func (f RegistererFunc) EnumSliceVar(p *[]string, name string, value []string, sep string, choices []string, usage string) { //nolint: golines
+	enumDefaults(name, value, choices)
+	*p = value
+	v := EnumSliceVar(p, sep, identity, choices...)
+	f(v, name, Usage(v, usage))
+}
@@ -0,0 +1,139 @@
+package values_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/values"
+)
+
+type level int
+
+const (
+	levelDebug level = iota
+	levelInfo
+	levelError
+)
+
+func (l level) String() string { return [...]string{"debug", "info", "error"}[l] }
+
+func ExampleEnum() {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	reg := values.FlagSetRegisterer(fs)
+	format := reg.Enum("format", "text", []string{"text", "json"}, "log format")
+	lvl := levelInfo
+	v := values.EnumVar(&lvl, level.String, levelDebug, levelInfo, levelError)
+	fs.Var(v, "level", values.Usage(v, "log level"))
+
+	fs.Parse([]string{"-level", "debug", "-format", "json"})
+	fmt.Println(*format, lvl == levelDebug)
+	fmt.Println(fs.Set("format", "xml"))
+	fs.PrintDefaults()
+
+	// Output:
+	// json true
+	// "xml" is not one of text, json
+	//   -format value
+	//     	log format (one of text, json) (default text)
+	//   -level value
+	//     	log level (one of debug, info, error) (default info)
+}
+
+func TestEnum(t *testing.T) {
+	choices := []level{levelDebug, levelInfo, levelError}
+
+	testCases := []struct {
+		name   string
+		value  flag.Value
+		args   []string
+		expect any
+	}{
+		{"Enum", values.Enum(level.String, choices...), []string{"info"}, levelInfo},
+		{"EnumList", values.EnumList(level.String, choices...), []string{"info", "error"}, []level{levelInfo, levelError}},
+		{"EnumSlice", values.EnumSlice(",", level.String, choices...), []string{"debug,error"}, []level{levelDebug, levelError}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, arg := range tc.args {
+				require.NoError(t, tc.value.Set(arg))
+			}
+			require.Equal(t, tc.expect, tc.value.(flag.Getter).Get())
+			require.EqualError(t, tc.value.Set("warn"), `"warn" is not one of debug, info, error`)
+		})
+	}
+
+	t.Run("Var", func(t *testing.T) {
+		var l level
+		var ls, ss []level
+		require.NoError(t, values.EnumVar(&l, level.String, choices...).Set("error"))
+		require.NoError(t, values.EnumListVar(&ls, level.String, choices...).Set("error"))
+		require.NoError(t, values.EnumSliceVar(&ss, "+", level.String, choices...).Set("info+error"))
+		require.Equal(t, levelError, l)
+		require.Equal(t, []level{levelError}, ls)
+		require.Equal(t, []level{levelInfo, levelError}, ss)
+	})
+}
+
+func TestUsage(t *testing.T) {
+	v := values.Enum(level.String, levelDebug, levelInfo, levelError)
+	require.Equal(t, "log level (one of debug, info, error)", values.Usage(v, "log level"))
+	require.Equal(t, "log level (one of debug, info, error)", values.Usage(values.Track(v), "log level"))
+	require.Equal(t, "log level", values.Usage(values.Basic[int](), "log level"))
+	require.Equal(t, "log level", values.Usage(values.Track(values.Basic[int]()), "log level"))
+}
+
+func TestEnumComplete(t *testing.T) {
+	type completer interface{ Complete(prefix string) []string }
+
+	choices := []string{"eu-west", "eu-north", "us-east"}
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	reg := values.FlagSetRegisterer(fs)
+	reg.Enum("region", "", choices, "a region")
+	reg.EnumSlice("regions", nil, ",", choices, "regions")
+	reg.Tracked().EnumList("tracked", nil, choices, "regions")
+
+	testCases := []struct {
+		flag   string
+		prefix string
+		expect []string
+	}{
+		{"region", "", choices},
+		{"region", "eu", []string{"eu-west", "eu-north"}},
+		{"region", "ap", []string{}},
+		{"regions", "eu-west,u", []string{"eu-west,us-east"}},
+		{"tracked", "us", []string{"us-east"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.flag+" "+tc.prefix, func(t *testing.T) {
+			c, ok := fs.Lookup(tc.flag).Value.(completer)
+			require.True(t, ok)
+			require.Equal(t, tc.expect, c.Complete(tc.prefix))
+		})
+	}
+}
+
+func TestRegisterer_enumDefaults(t *testing.T) {
+	choices := []string{"text", "json"}
+	reg := values.FlagSetRegisterer(flag.NewFlagSet("", flag.ContinueOnError))
+
+	require.NotPanics(t, func() { reg.Enum("empty", "", choices, "no choice") })
+	require.PanicsWithValue(t, `flag -enum: default value "xml" is not one of text, json`,
+		func() { reg.Enum("enum", "xml", choices, "") })
+	require.PanicsWithValue(t, `flag -enum-var: default value "xml" is not one of text, json`,
+		func() { reg.EnumVar(new(string), "enum-var", "xml", choices, "") })
+	require.PanicsWithValue(t, `flag -list: default value "" is not one of text, json`,
+		func() { reg.EnumList("list", []string{"text", ""}, choices, "") })
+	require.PanicsWithValue(t, `flag -list-var: default value "xml" is not one of text, json`,
+		func() { reg.EnumListVar(new([]string), "list-var", []string{"xml"}, choices, "") })
+	require.PanicsWithValue(t, `flag -slice: default value "xml" is not one of text, json`,
+		func() { reg.EnumSlice("slice", []string{"xml"}, ",", choices, "") })
+	require.PanicsWithValue(t, `flag -slice-var: default value "xml" is not one of text, json`,
+		func() { reg.EnumSliceVar(new([]string), "slice-var", []string{"json", "xml"}, ",", choices, "") })
+}
@@ -0,0 +1,179 @@
+package values
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvRegisterer registers named flags in a [flag.FlagSet] and sets the [flag.Value]
+// with the matching environment variable, ajusting usage accordingly.
+//
+// The environment variable name is derived from the flag name by:
+//
+//   - replacing dashes and dots with underscores
+//   - transforming to upper case
+//   - prepending with Prefix
+//
+// Values bound by [RegistererFunc.Bind] to fields with an env tag use the tagged name instead.
+//
+// The flag is set using [flag.FlagSet.Set], so that [flag.FlagSet.Visit] reports
+// it as set, like it would if it was set on the command line. Flag groups of package
+// [github.com/rlibaert/flag/cli] still let flags set on the command line override it,
+// see [github.com/rlibaert/flag/cli.Command.FlagsMutuallyExclusive].
+// Values wrapped by [Track] record the environment variable as their [Origin].
+//
+// Environment variables failing to set their flag value are collected and reported by [EnvRegisterer.Err].
+type EnvRegisterer struct {
+	FlagSet *flag.FlagSet
+	Prefix  string
+
+	// LookupEnv retrieves the value of environment variables, [os.LookupEnv] if nil.
+	LookupEnv func(key string) (string, bool)
+
+	// Dotenv holds the variables read from a dotenv file, see [ReadDotenv], used for the
+	// environment variables LookupEnv does not retrieve. Values read from it record
+	// DotenvName, ".env" if empty, in their [Origin].
+	Dotenv     Dotenv
+	DotenvName string
+
+	// FileSuffix, if not empty, enables reading values from files, such as mounted secrets.
+	// The environment variable suffixed with FileSuffix (e.g. "_FILE") then holds the path
+	// of a file whose content, stripped of its trailing newline, is the value of the flag.
+	// It is an error to set both environment variables.
+	FileSuffix string
+
+	errs []error
+}
+
+// EnvError records an environment variable failing to set its flag value.
+type EnvError struct {
+	Env   string // name of the environment variable
+	Flag  string // name of the flag
+	Value string // value of the environment variable
+	Err   error
+}
+
+func (e *EnvError) Error() string {
+	return fmt.Sprintf("invalid value %q for flag -%s from env $%s: %v", e.Value, e.Flag, e.Env, e.Err)
+}
+
+func (e *EnvError) Unwrap() error { return e.Err }
+
+// EnvConflictError records both an environment variable and its FileSuffix variant being set.
+type EnvConflictError struct {
+	Env     string // name of the environment variable
+	FileEnv string // name of its FileSuffix variant
+	Flag    string // name of the flag
+}
+
+func (e *EnvConflictError) Error() string {
+	return fmt.Sprintf("$%s and $%s are both set for flag -%s", e.Env, e.FileEnv, e.Flag)
+}
+
+var envReplacer = strings.NewReplacer("-", "_", ".", "_") //nolint: gochecknoglobals // read-only replacer
+
+// EnvName returns the name of the environment variable matching the named flag.
+func (r *EnvRegisterer) EnvName(name string) string {
+	return r.Prefix + strings.ToUpper(envReplacer.Replace(name))
+}
+
+// envNamer is implemented by [flag.Value] naming the environment variable setting them.
+type envNamer interface {
+	envName() string
+}
+
+// Register registers the named flag and sets it with the matching environment variable, if any.
+// It has the signature of a [RegistererFunc].
+func (r *EnvRegisterer) Register(value flag.Value, name, usage string) {
+	envname := r.EnvName(name)
+	if n, ok := value.(envNamer); ok && n.envName() != "" {
+		envname = n.envName()
+	}
+	if r.FileSuffix == "" {
+		r.FlagSet.Var(value, name, fmt.Sprintf("%s (env $%s)", usage, envname))
+	} else {
+		r.FlagSet.Var(value, name, fmt.Sprintf("%s (env $%s, $%s%s)", usage, envname, envname, r.FileSuffix))
+	}
+
+	origin, raw, conflict := r.lookup(envname)
+	if conflict {
+		r.errs = append(r.errs, &EnvConflictError{Env: envname, FileEnv: origin.Key, Flag: name})
+		return
+	}
+	if origin.Key == "" {
+		return
+	}
+
+	val := raw
+	var err error
+	if origin.Key != envname {
+		val, err = readEnvFile(raw)
+	}
+	if err == nil {
+		err = setFrom(r.FlagSet, name, val, origin)
+	}
+	if err != nil {
+		r.errs = append(r.errs, &EnvError{Env: origin.Key, Flag: name, Value: raw, Err: err})
+	}
+}
+
+// lookup returns the origin and value of the environment variable set for envname,
+// either envname itself or its FileSuffix variant. The origin key is empty if neither is set,
+// and conflict reports both being set.
+func (r *EnvRegisterer) lookup(envname string) (origin Origin, val string, conflict bool) {
+	val, dotenv, ok := r.lookupEnv(envname)
+	fileenv, path, fileDotenv, fileOk := envname+r.FileSuffix, "", "", false
+	if r.FileSuffix != "" {
+		path, fileDotenv, fileOk = r.lookupEnv(fileenv)
+	}
+
+	switch {
+	case ok && fileOk:
+		return Origin{Source: SourceEnv, Key: fileenv, Dotenv: fileDotenv}, path, true
+	case ok:
+		return Origin{Source: SourceEnv, Key: envname, Dotenv: dotenv}, val, false
+	case fileOk:
+		return Origin{Source: SourceEnv, Key: fileenv, Dotenv: fileDotenv}, path, false
+	default:
+		return Origin{}, "", false
+	}
+}
+
+// lookupEnv retrieves the value of the environment variable named by key, along with
+// the name of the dotenv file it was read from, if any.
+func (r *EnvRegisterer) lookupEnv(key string) (string, string, bool) {
+	lookup := r.LookupEnv
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	if val, ok := lookup(key); ok {
+		return val, "", true
+	}
+
+	val, ok := r.Dotenv[key]
+	if !ok {
+		return "", "", false
+	}
+	if r.DotenvName == "" {
+		return val, ".env", true
+	}
+	return val, r.DotenvName, true
+}
+
+// readEnvFile returns the content of the file at path, stripped of its trailing newline.
+func readEnvFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(b), "\n"), "\r"), nil
+}
+
+// Err returns the errors of the environment variables failing to set their flag value,
+// joined with [errors.Join], or nil if there were none. See [EnvError] and [EnvConflictError].
+func (r *EnvRegisterer) Err() error {
+	return errors.Join(r.errs...)
+}
@@ -0,0 +1,102 @@
+package values_test
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/values"
+)
+
+func ExampleEnvRegisterer() {
+	os.Setenv("FOO_COUNT", "abc")
+	defer os.Unsetenv("FOO_COUNT")
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	r := &values.EnvRegisterer{FlagSet: fs, Prefix: "FOO_"}
+	values.RegistererFunc(r.Register).Int("count", 10, "number of items")
+	fmt.Println(r.Err())
+
+	// Output:
+	// invalid value "abc" for flag -count from env $FOO_COUNT: strconv.ParseInt: parsing "abc": invalid syntax
+}
+
+func TestEnvRegisterer(t *testing.T) {
+	t.Setenv("FOO_INT", "42")
+	t.Setenv("FOO_BAD_INT", "notint")
+	t.Setenv("FOO_BAD_BOOL", "notbool")
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	r := &values.EnvRegisterer{FlagSet: fs, Prefix: "FOO_"}
+	reg := values.RegistererFunc(r.Register)
+	i := reg.Int("int", 12, "an int")
+	reg.Int("bad-int", 12, "an int")
+	reg.Bool("bad.bool", false, "a bool")
+	reg.Int("unset", 12, "an int")
+
+	require.Equal(t, 42, *i)
+	require.Equal(t, "FOO_BAD_BOOL", r.EnvName("bad.bool"))
+
+	err := r.Err()
+	require.EqualError(t, err, `invalid value "notint" for flag -bad-int from env $FOO_BAD_INT: `+
+		`strconv.ParseInt: parsing "notint": invalid syntax`+"\n"+
+		`invalid value "notbool" for flag -bad.bool from env $FOO_BAD_BOOL: `+
+		`strconv.ParseBool: parsing "notbool": invalid syntax`)
+	require.ErrorIs(t, err, strconv.ErrSyntax)
+
+	var envErr *values.EnvError
+	require.True(t, errors.As(err, &envErr))
+	require.Equal(t, &values.EnvError{Env: "FOO_BAD_INT", Flag: "bad-int", Value: "notint", Err: envErr.Err}, envErr)
+
+	t.Run("no errors", func(t *testing.T) {
+		r := &values.EnvRegisterer{FlagSet: flag.NewFlagSet("", flag.ContinueOnError), Prefix: "FOO_"}
+		values.RegistererFunc(r.Register).Int("int", 12, "an int")
+		require.NoError(t, r.Err())
+	})
+}
+
+func TestEnvRegisterer_fileSuffix(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "secret")
+	require.NoError(t, os.WriteFile(secret, []byte("s3cr3t\n"), 0o600))
+	bad := filepath.Join(dir, "bad")
+	require.NoError(t, os.WriteFile(bad, []byte("notint\n"), 0o600))
+
+	t.Setenv("FOO_PASSWORD_FILE", secret)
+	t.Setenv("FOO_USER", "admin")
+	t.Setenv("FOO_BOTH", "foo")
+	t.Setenv("FOO_BOTH_FILE", secret)
+	t.Setenv("FOO_MISSING_FILE", filepath.Join(dir, "missing"))
+	t.Setenv("FOO_BAD_FILE", bad)
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	r := &values.EnvRegisterer{FlagSet: fs, Prefix: "FOO_", FileSuffix: "_FILE"}
+	reg := values.RegistererFunc(r.Register).Tracked()
+	password := reg.String("password", "", "a password")
+	user := reg.String("user", "", "a user")
+	reg.String("both", "", "a string")
+	reg.String("missing", "", "a string")
+	reg.Int("bad", 0, "an int")
+
+	require.Equal(t, "s3cr3t", *password)
+	require.Equal(t, "admin", *user)
+	require.Equal(t, "a password (env $FOO_PASSWORD, $FOO_PASSWORD_FILE)", fs.Lookup("password").Usage)
+
+	origin, _ := values.OriginOf(fs.Lookup("password").Value)
+	require.Equal(t, values.Origin{Source: values.SourceEnv, Key: "FOO_PASSWORD_FILE"}, origin)
+
+	err := r.Err()
+	require.ErrorContains(t, err, "$FOO_BOTH and $FOO_BOTH_FILE are both set for flag -both")
+	require.ErrorAs(t, err, new(*values.EnvConflictError))
+	require.ErrorContains(t, err, fmt.Sprintf(
+		"invalid value %q for flag -missing from env $FOO_MISSING_FILE: open ", filepath.Join(dir, "missing")))
+	require.ErrorContains(t, err, fmt.Sprintf(
+		"invalid value %q for flag -bad from env $FOO_BAD_FILE: strconv.ParseInt", bad))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,456 @@
+package values
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+)
+
+// DuplicateKeys defines how map values handle keys set more than once.
+// Keys of the default value are not considered, they can always be overridden, and neither are
+// keys set from a source of lower precedence, such as an environment variable or a configuration
+// file overridden on the command line, see [SourceSetter].
+type DuplicateKeys int
+
+const (
+	DuplicateKeysError    DuplicateKeys = iota // setting a key more than once is an error
+	DuplicateKeysLastWins                      // the last value set for a key is kept
+)
+
+// genericMap implements [flag.Value] for a map, set one entry at a time if sep is empty,
+// or all at once otherwise.
+type genericMap[K comparable, V any] struct {
+	sep         string
+	kvsep       string
+	dup         DuplicateKeys
+	parseKey    func(string) (K, error)
+	formatKey   func(K) string
+	parseValue  func(string) (V, error)
+	formatValue func(V) string
+	values      *map[K]V
+	set         map[K]bool // keys set from source, as opposed to default ones or those of lower sources
+	source      Source     // source of the keys in set
+	next        *Source    // source of the next set, if not the command line
+}
+
+// SetSource implements [SourceSetter].
+func (v *genericMap[K, V]) SetSource(s Source) { v.next = &s }
+
+func (v *genericMap[K, V]) Set(s string) error {
+	source := SourceCommandLine
+	if v.next != nil {
+		source, v.next = *v.next, nil
+	}
+	if source != v.source {
+		v.set, v.source = map[K]bool{}, source
+	}
+
+	entries := []string{s}
+	m := make(map[K]V, len(*v.values)+1)
+	if v.sep == "" {
+		maps.Copy(m, *v.values)
+	} else {
+		entries = strings.Split(s, v.sep)
+		v.set = map[K]bool{}
+	}
+
+	for _, entry := range entries {
+		ks, vs, ok := strings.Cut(entry, v.kvsep)
+		if !ok {
+			return fmt.Errorf("missing %q in %q", v.kvsep, entry)
+		}
+		key, err := v.parseKey(ks)
+		if err != nil {
+			return err
+		}
+		val, err := v.parseValue(vs)
+		if err != nil {
+			return err
+		}
+		if v.set[key] && v.dup == DuplicateKeysError {
+			return fmt.Errorf("duplicate key %q", ks)
+		}
+		m[key], v.set[key] = val, true
+	}
+
+	*v.values = m
+	return nil
+}
+
+func (v *genericMap[K, V]) String() string {
+	if v.values == nil || len(*v.values) == 0 {
+		return ""
+	}
+
+	entries := make([]string, 0, len(*v.values))
+	for key, val := range *v.values {
+		entries = append(entries, v.formatKey(key)+v.kvsep+v.formatValue(val))
+	}
+	sort.Strings(entries)
+
+	if v.sep == "" {
+		return fmt.Sprint(entries)
+	}
+	return strings.Join(entries, v.sep)
+}
+
+func (v *genericMap[K, V]) Get() any {
+	return *v.values
+}
+
+// GenericMap declares a map-style [flag.Value] implemented using the parse & format functions
+// of keys and values. The input strings are split around sep into entries, themselves split
+// around kvsep into key and value before parsing. All entries are set at once every time
+// the flag is invoked. Duplicate keys are handled according to dup.
+// The actual value type is map[K]V.
+func GenericMap[K comparable, V any](
+	sep, kvsep string, dup DuplicateKeys,
+	parseKey func(string) (K, error), formatKey func(K) string,
+	parseValue func(string) (V, error), formatValue func(V) string,
+) flag.Value {
+	return GenericMapVar(new(map[K]V), sep, kvsep, dup, parseKey, formatKey, parseValue, formatValue)
+}
+
+// GenericMapVar is like [GenericMap] but stores the values in p.
+func GenericMapVar[K comparable, V any](
+	p *map[K]V, sep, kvsep string, dup DuplicateKeys,
+	parseKey func(string) (K, error), formatKey func(K) string,
+	parseValue func(string) (V, error), formatValue func(V) string,
+) flag.Value {
+	return &genericMap[K, V]{
+		sep, kvsep, dup, parseKey, formatKey, parseValue, formatValue, p, map[K]bool{}, SourceDefault, nil,
+	}
+}
+
+// GenericMapList declares a map-style [flag.Value] implemented using the parse & format functions
+// of keys and values. The input strings are split around kvsep into key and value before parsing.
+// A single entry is added every time the flag is invoked. Duplicate keys are handled according to dup.
+// The actual value type is map[K]V.
+func GenericMapList[K comparable, V any](
+	kvsep string, dup DuplicateKeys,
+	parseKey func(string) (K, error), formatKey func(K) string,
+	parseValue func(string) (V, error), formatValue func(V) string,
+) flag.Value {
+	return GenericMapListVar(new(map[K]V), kvsep, dup, parseKey, formatKey, parseValue, formatValue)
+}
+
+// GenericMapListVar is like [GenericMapList] but stores the values in p.
+func GenericMapListVar[K comparable, V any](
+	p *map[K]V, kvsep string, dup DuplicateKeys,
+	parseKey func(string) (K, error), formatKey func(K) string,
+	parseValue func(string) (V, error), formatValue func(V) string,
+) flag.Value {
+	return &genericMap[K, V]{
+		"", kvsep, dup, parseKey, formatKey, parseValue, formatValue, p, map[K]bool{}, SourceDefault, nil,
+	}
+}
+
+// basicKey is the subset of basic types usable as map keys.
+type basicKey interface {
+	basic
+	comparable
+}
+
+// BasicMap declares a map-style [flag.Value] for basic Go types, see [GenericMap].
+// The actual value type is map[K]V.
+func BasicMap[K basicKey, V basic](sep, kvsep string, dup DuplicateKeys) flag.Value {
+	return GenericMap(sep, kvsep, dup, parseBasic[K], formatBasic[K], parseBasic[V], formatBasic[V])
+}
+
+// BasicMapVar is like [BasicMap] but stores the values in p.
+func BasicMapVar[K basicKey, V basic](p *map[K]V, sep, kvsep string, dup DuplicateKeys) flag.Value {
+	return GenericMapVar(p, sep, kvsep, dup, parseBasic[K], formatBasic[K], parseBasic[V], formatBasic[V])
+}
+
+// BasicMapList declares a map-style [flag.Value] for basic Go types, see [GenericMapList].
+// The actual value type is map[K]V.
+func BasicMapList[K basicKey, V basic](kvsep string, dup DuplicateKeys) flag.Value {
+	return GenericMapList(kvsep, dup, parseBasic[K], formatBasic[K], parseBasic[V], formatBasic[V])
+}
+
+// BasicMapListVar is like [BasicMapList] but stores the values in p.
+func BasicMapListVar[K basicKey, V basic](p *map[K]V, kvsep string, dup DuplicateKeys) flag.Value {
+	return GenericMapListVar(p, kvsep, dup, parseBasic[K], formatBasic[K], parseBasic[V], formatBasic[V])
+}
+
+// StringMap defines a map-style string flag with specified name, default value, and usage string.
+// The input strings are split around sep into entries, themselves split around kvsep, see [GenericMap].
+// The return value is the address of a string map that stores the values of the flag.
+func (f RegistererFunc) StringMap(
+	name string, value map[string]string, sep, kvsep string, dup DuplicateKeys, usage string,
+) *map[string]string {
+	f(BasicMapVar(&value, sep, kvsep, dup), name, usage)
+	return &value
+}
+
+// StringMapVar defines a map-style string flag with specified name, default value, and usage string.
+// The input strings are split around sep into entries, themselves split around kvsep, see [GenericMap].
+// The argument p points to a string map variable in which to store the values of the flag.
+func (f RegistererFunc) StringMapVar(
+	p *map[string]string, name string, value map[string]string, sep, kvsep string, dup DuplicateKeys, usage string,
+) {
+	*p = value
+	f(BasicMapVar(p, sep, kvsep, dup), name, usage)
+}
+
+// StringMapList defines a list-style string map flag with specified name, default value, and usage string.
+// The input strings are split around kvsep, see [GenericMapList].
+// The return value is the address of a string map that stores the values of the flag.
+func (f RegistererFunc) StringMapList(
+	name string, value map[string]string, kvsep string, dup DuplicateKeys, usage string,
+) *map[string]string {
+	f(BasicMapListVar(&value, kvsep, dup), name, usage)
+	return &value
+}
+
+// StringMapListVar defines a list-style string map flag with specified name, default value, and usage string.
+// The input strings are split around kvsep, see [GenericMapList].
+// The argument p points to a string map variable in which to store the values of the flag.
+func (f RegistererFunc) StringMapListVar(
+	p *map[string]string, name string, value map[string]string, kvsep string, dup DuplicateKeys, usage string,
+) {
+	*p = value
+	f(BasicMapListVar(p, kvsep, dup), name, usage)
+}
+
+// StringBoolMap defines a map-style flag of bool values with string keys, with specified name, default value,
+// and usage string. The input strings are split around sep into entries, themselves split around kvsep,
+// see [GenericMap]. The return value is the address of a map that stores the values of the flag.
+func (f RegistererFunc) StringBoolMap(
+	name string, value map[string]bool, sep, kvsep string, dup DuplicateKeys, usage string,
+) *map[string]bool {
+	f(BasicMapVar(&value, sep, kvsep, dup), name, usage)
+	return &value
+}
+
+// StringBoolMapVar defines a map-style flag of bool values with string keys, with specified name, default value,
+// and usage string. The input strings are split around sep into entries, themselves split around kvsep,
+// see [GenericMap]. The argument p points to a map variable in which to store the values of the flag.
+func (f RegistererFunc) StringBoolMapVar(
+	p *map[string]bool, name string, value map[string]bool, sep, kvsep string, dup DuplicateKeys, usage string,
+) {
+	*p = value
+	f(BasicMapVar(p, sep, kvsep, dup), name, usage)
+}
+
+// StringBoolMapList defines a list-style map flag of bool values with string keys, with specified name,
+// default value, and usage string. The input strings are split around kvsep, see [GenericMapList].
+// The return value is the address of a map that stores the values of the flag.
+func (f RegistererFunc) StringBoolMapList(
+	name string, value map[string]bool, kvsep string, dup DuplicateKeys, usage string,
+) *map[string]bool {
+	f(BasicMapListVar(&value, kvsep, dup), name, usage)
+	return &value
+}
+
+// StringBoolMapListVar defines a list-style map flag of bool values with string keys, with specified name,
+// default value, and usage string. The input strings are split around kvsep, see [GenericMapList].
+// The argument p points to a map variable in which to store the values of the flag.
+func (f RegistererFunc) StringBoolMapListVar(
+	p *map[string]bool, name string, value map[string]bool, kvsep string, dup DuplicateKeys, usage string,
+) {
+	*p = value
+	f(BasicMapListVar(p, kvsep, dup), name, usage)
+}
+
+// StringIntMap defines a map-style flag of int values with string keys, with specified name, default value,
+// and usage string. The input strings are split around sep into entries, themselves split around kvsep,
+// see [GenericMap]. The return value is the address of a map that stores the values of the flag.
+func (f RegistererFunc) StringIntMap(
+	name string, value map[string]int, sep, kvsep string, dup DuplicateKeys, usage string,
+) *map[string]int {
+	f(BasicMapVar(&value, sep, kvsep, dup), name, usage)
+	return &value
+}
+
+// StringIntMapVar defines a map-style flag of int values with string keys, with specified name, default value,
+// and usage string. The input strings are split around sep into entries, themselves split around kvsep,
+// see [GenericMap]. The argument p points to a map variable in which to store the values of the flag.
+func (f RegistererFunc) StringIntMapVar(
+	p *map[string]int, name string, value map[string]int, sep, kvsep string, dup DuplicateKeys, usage string,
+) {
+	*p = value
+	f(BasicMapVar(p, sep, kvsep, dup), name, usage)
+}
+
+// StringIntMapList defines a list-style map flag of int values with string keys, with specified name,
+// default value, and usage string. The input strings are split around kvsep, see [GenericMapList].
+// The return value is the address of a map that stores the values of the flag.
+func (f RegistererFunc) StringIntMapList(
+	name string, value map[string]int, kvsep string, dup DuplicateKeys, usage string,
+) *map[string]int {
+	f(BasicMapListVar(&value, kvsep, dup), name, usage)
+	return &value
+}
+
+// StringIntMapListVar defines a list-style map flag of int values with string keys, with specified name,
+// default value, and usage string. The input strings are split around kvsep, see [GenericMapList].
+// The argument p points to a map variable in which to store the values of the flag.
+func (f RegistererFunc) StringIntMapListVar(
+	p *map[string]int, name string, value map[string]int, kvsep string, dup DuplicateKeys, usage string,
+) {
+	*p = value
+	f(BasicMapListVar(p, kvsep, dup), name, usage)
+}
+
+// StringInt64Map defines a map-style flag of int64 values with string keys, with specified name, default value,
+// and usage string. The input strings are split around sep into entries, themselves split around kvsep,
+// see [GenericMap]. The return value is the address of a map that stores the values of the flag.
+func (f RegistererFunc) StringInt64Map(
+	name string, value map[string]int64, sep, kvsep string, dup DuplicateKeys, usage string,
+) *map[string]int64 {
+	f(BasicMapVar(&value, sep, kvsep, dup), name, usage)
+	return &value
+}
+
+// StringInt64MapVar defines a map-style flag of int64 values with string keys, with specified name, default value,
+// and usage string. The input strings are split around sep into entries, themselves split around kvsep,
+// see [GenericMap]. The argument p points to a map variable in which to store the values of the flag.
+func (f RegistererFunc) StringInt64MapVar(
+	p *map[string]int64, name string, value map[string]int64, sep, kvsep string, dup DuplicateKeys, usage string,
+) {
+	*p = value
+	f(BasicMapVar(p, sep, kvsep, dup), name, usage)
+}
+
+// StringInt64MapList defines a list-style map flag of int64 values with string keys, with specified name,
+// default value, and usage string. The input strings are split around kvsep, see [GenericMapList].
+// The return value is the address of a map that stores the values of the flag.
+func (f RegistererFunc) StringInt64MapList(
+	name string, value map[string]int64, kvsep string, dup DuplicateKeys, usage string,
+) *map[string]int64 {
+	f(BasicMapListVar(&value, kvsep, dup), name, usage)
+	return &value
+}
+
+// StringInt64MapListVar defines a list-style map flag of int64 values with string keys, with specified name,
+// default value, and usage string. The input strings are split around kvsep, see [GenericMapList].
+// The argument p points to a map variable in which to store the values of the flag.
+func (f RegistererFunc) StringInt64MapListVar(
+	p *map[string]int64, name string, value map[string]int64, kvsep string, dup DuplicateKeys, usage string,
+) {
+	*p = value
+	f(BasicMapListVar(p, kvsep, dup), name, usage)
+}
+
+// StringUintMap defines a map-style flag of uint values with string keys, with specified name, default value,
+// and usage string. The input strings are split around sep into entries, themselves split around kvsep,
+// see [GenericMap]. The return value is the address of a map that stores the values of the flag.
+func (f RegistererFunc) StringUintMap(
+	name string, value map[string]uint, sep, kvsep string, dup DuplicateKeys, usage string,
+) *map[string]uint {
+	f(BasicMapVar(&value, sep, kvsep, dup), name, usage)
+	return &value
+}
+
+// StringUintMapVar defines a map-style flag of uint values with string keys, with specified name, default value,
+// and usage string. The input strings are split around sep into entries, themselves split around kvsep,
+// see [GenericMap]. The argument p points to a map variable in which to store the values of the flag.
+func (f RegistererFunc) StringUintMapVar(
+	p *map[string]uint, name string, value map[string]uint, sep, kvsep string, dup DuplicateKeys, usage string,
+) {
+	*p = value
+	f(BasicMapVar(p, sep, kvsep, dup), name, usage)
+}
+
+// StringUintMapList defines a list-style map flag of uint values with string keys, with specified name,
+// default value, and usage string. The input strings are split around kvsep, see [GenericMapList].
+// The return value is the address of a map that stores the values of the flag.
+func (f RegistererFunc) StringUintMapList(
+	name string, value map[string]uint, kvsep string, dup DuplicateKeys, usage string,
+) *map[string]uint {
+	f(BasicMapListVar(&value, kvsep, dup), name, usage)
+	return &value
+}
+
+// StringUintMapListVar defines a list-style map flag of uint values with string keys, with specified name,
+// default value, and usage string. The input strings are split around kvsep, see [GenericMapList].
+// The argument p points to a map variable in which to store the values of the flag.
+func (f RegistererFunc) StringUintMapListVar(
+	p *map[string]uint, name string, value map[string]uint, kvsep string, dup DuplicateKeys, usage string,
+) {
+	*p = value
+	f(BasicMapListVar(p, kvsep, dup), name, usage)
+}
+
+// StringUint64Map defines a map-style flag of uint64 values with string keys, with specified name, default value,
+// and usage string. The input strings are split around sep into entries, themselves split around kvsep,
+// see [GenericMap]. The return value is the address of a map that stores the values of the flag.
+func (f RegistererFunc) StringUint64Map(
+	name string, value map[string]uint64, sep, kvsep string, dup DuplicateKeys, usage string,
+) *map[string]uint64 {
+	f(BasicMapVar(&value, sep, kvsep, dup), name, usage)
+	return &value
+}
+
+// StringUint64MapVar defines a map-style flag of uint64 values with string keys, with specified name, default value,
+// and usage string. The input strings are split around sep into entries, themselves split around kvsep,
+// see [GenericMap]. The argument p points to a map variable in which to store the values of the flag.
+func (f RegistererFunc) StringUint64MapVar(
+	p *map[string]uint64, name string, value map[string]uint64, sep, kvsep string, dup DuplicateKeys, usage string,
+) {
+	*p = value
+	f(BasicMapVar(p, sep, kvsep, dup), name, usage)
+}
+
+// StringUint64MapList defines a list-style map flag of uint64 values with string keys, with specified name,
+// default value, and usage string. The input strings are split around kvsep, see [GenericMapList].
+// The return value is the address of a map that stores the values of the flag.
+func (f RegistererFunc) StringUint64MapList(
+	name string, value map[string]uint64, kvsep string, dup DuplicateKeys, usage string,
+) *map[string]uint64 {
+	f(BasicMapListVar(&value, kvsep, dup), name, usage)
+	return &value
+}
+
+// StringUint64MapListVar defines a list-style map flag of uint64 values with string keys, with specified name,
+// default value, and usage string. The input strings are split around kvsep, see [GenericMapList].
+// The argument p points to a map variable in which to store the values of the flag.
+func (f RegistererFunc) StringUint64MapListVar(
+	p *map[string]uint64, name string, value map[string]uint64, kvsep string, dup DuplicateKeys, usage string,
+) {
+	*p = value
+	f(BasicMapListVar(p, kvsep, dup), name, usage)
+}
+
+// StringFloat64Map defines a map-style flag of float64 values with string keys, with specified name, default value,
+// and usage string. The input strings are split around sep into entries, themselves split around kvsep,
+// see [GenericMap]. The return value is the address of a map that stores the values of the flag.
+func (f RegistererFunc) StringFloat64Map(
+	name string, value map[string]float64, sep, kvsep string, dup DuplicateKeys, usage string,
+) *map[string]float64 {
+	f(BasicMapVar(&value, sep, kvsep, dup), name, usage)
+	return &value
+}
+
+// StringFloat64MapVar defines a map-style flag of float64 values with string keys, with specified name, default value,
+// and usage string. The input strings are split around sep into entries, themselves split around kvsep,
+// see [GenericMap]. The argument p points to a map variable in which to store the values of the flag.
+func (f RegistererFunc) StringFloat64MapVar(
+	p *map[string]float64, name string, value map[string]float64, sep, kvsep string, dup DuplicateKeys, usage string,
+) {
+	*p = value
+	f(BasicMapVar(p, sep, kvsep, dup), name, usage)
+}
+
+// StringFloat64MapList defines a list-style map flag of float64 values with string keys, with specified name,
+// default value, and usage string. The input strings are split around kvsep, see [GenericMapList].
+// The return value is the address of a map that stores the values of the flag.
+func (f RegistererFunc) StringFloat64MapList(
+	name string, value map[string]float64, kvsep string, dup DuplicateKeys, usage string,
+) *map[string]float64 {
+	f(BasicMapListVar(&value, kvsep, dup), name, usage)
+	return &value
+}
+
+// StringFloat64MapListVar defines a list-style map flag of float64 values with string keys, with specified name,
+// default value, and usage string. The input strings are split around kvsep, see [GenericMapList].
+// The argument p points to a map variable in which to store the values of the flag.
+func (f RegistererFunc) StringFloat64MapListVar(
+	p *map[string]float64, name string, value map[string]float64, kvsep string, dup DuplicateKeys, usage string,
+) {
+	*p = value
+	f(BasicMapListVar(p, kvsep, dup), name, usage)
+}
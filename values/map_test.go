@@ -0,0 +1,162 @@
+package values_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/values"
+)
+
+func ExampleGenericMap() {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	reg := values.FlagSetRegisterer(fs)
+	labels := reg.StringMapList("label", nil, "=", values.DuplicateKeysError, "labels")
+	headers := reg.StringMap("headers", map[string]string{"a": "0"}, ",", "=", values.DuplicateKeysLastWins, "headers")
+	fs.Parse([]string{"-label", "env=prod", "-label", "team=core", "-headers", "a=1,b=2,a=3"})
+	fmt.Println(*labels, *headers)
+	fs.PrintDefaults()
+
+	// Output:
+	// map[env:prod team:core] map[a:3 b:2]
+	//   -headers value
+	//     	headers (default a=0)
+	//   -label value
+	//     	labels
+}
+
+func TestGenericMap(t *testing.T) {
+	testCases := []struct {
+		name   string
+		value  flag.Value
+		args   []string
+		expect any
+		str    string
+	}{
+		{
+			"BasicMap", values.BasicMap[string, int](",", "=", values.DuplicateKeysError),
+			[]string{"a=1,b=2", "c=3,b=4"}, map[string]int{"c": 3, "b": 4}, "b=4,c=3",
+		},
+		{
+			"BasicMapList", values.BasicMapList[int, bool](":", values.DuplicateKeysError),
+			[]string{"2:true", "1:false"}, map[int]bool{1: false, 2: true}, "[1:false 2:true]",
+		},
+		{
+			"BasicMapList last wins", values.BasicMapList[string, string]("=", values.DuplicateKeysLastWins),
+			[]string{"a=1", "a=2=3"}, map[string]string{"a": "2=3"}, "[a=2=3]",
+		},
+		{
+			"GenericMap", values.GenericMap(";", "->", values.DuplicateKeysError,
+				strconv.Atoi, strconv.Itoa, strconv.ParseBool, strconv.FormatBool),
+			[]string{"1->true;2->false"}, map[int]bool{1: true, 2: false}, "1->true;2->false",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Empty(t, tc.value.String())
+			for _, arg := range tc.args {
+				require.NoError(t, tc.value.Set(arg))
+			}
+			require.Equal(t, tc.expect, tc.value.(flag.Getter).Get())
+			require.Equal(t, tc.str, tc.value.String())
+		})
+	}
+}
+
+func TestGenericMap_errors(t *testing.T) {
+	dup := values.DuplicateKeysError
+	testCases := []struct {
+		name  string
+		value flag.Value
+		args  []string
+		err   string
+	}{
+		{"missing separator", values.BasicMap[string, string](",", "=", dup), []string{"a=1,b"}, `missing "=" in "b"`},
+		{"duplicate entry", values.BasicMap[string, string](",", "=", dup), []string{"a=1,a=2"}, `duplicate key "a"`},
+		{"duplicate set", values.BasicMapList[string, string]("=", dup), []string{"a=1", "a=2"}, `duplicate key "a"`},
+		{"invalid key", values.BasicMapList[int, string]("=", dup), []string{"a=1"}, `strconv.ParseInt: parsing "a": invalid syntax`},   //nolint: golines
+		{"invalid value", values.BasicMapList[string, int]("=", dup), []string{"a=b"}, `strconv.ParseInt: parsing "b": invalid syntax`}, //nolint: golines
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+			for _, arg := range tc.args {
+				err = tc.value.Set(arg)
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestRegisterer_maps(t *testing.T) {
+	defaults := map[string]string{"a": "0"}
+
+	var m, ml map[string]string
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	reg := values.FlagSetRegisterer(fs)
+	reg.StringMapVar(&m, "map", defaults, ",", "=", values.DuplicateKeysError, "a map")
+	reg.StringMapListVar(&ml, "map-list", defaults, "=", values.DuplicateKeysError, "a map")
+	list := reg.StringMapList("list", defaults, "=", values.DuplicateKeysError, "a map")
+	require.NoError(t, fs.Parse([]string{"-map", "b=1", "-map-list", "a=1", "-list", "b=1"}))
+
+	require.Equal(t, map[string]string{"b": "1"}, m)
+	require.Equal(t, map[string]string{"a": "1"}, ml, "default keys can be overridden")
+	require.Equal(t, map[string]string{"a": "0", "b": "1"}, *list)
+	require.Equal(t, map[string]string{"a": "0"}, defaults, "default value is not modified")
+	require.Equal(t, "[a=0]", fs.Lookup("list").DefValue)
+}
+
+func TestRegisterer_basicMaps(t *testing.T) {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	reg := values.FlagSetRegisterer(fs)
+	bools := reg.StringBoolMap("bools", nil, ",", "=", values.DuplicateKeysError, "bools")
+	ints := reg.StringIntMapList("ints", map[string]int{"a": 0}, "=", values.DuplicateKeysError, "ints")
+	var int64s map[string]int64
+	reg.StringInt64MapVar(&int64s, "int64s", nil, ",", "=", values.DuplicateKeysError, "int64s")
+	var uints map[string]uint
+	reg.StringUintMapListVar(&uints, "uints", nil, "=", values.DuplicateKeysLastWins, "uints")
+	uint64s := reg.StringUint64Map("uint64s", nil, ",", ":", values.DuplicateKeysError, "uint64s")
+	floats := reg.StringFloat64MapList("floats", nil, "=", values.DuplicateKeysError, "floats")
+
+	require.NoError(t, fs.Parse([]string{
+		"-bools", "a=true,b=false", "-ints", "b=1", "-int64s", "a=-1", "-uints", "a=1", "-uints", "a=2",
+		"-uint64s", "a:1", "-floats", "pi=3.14",
+	}))
+	require.Equal(t, map[string]bool{"a": true, "b": false}, *bools)
+	require.Equal(t, map[string]int{"a": 0, "b": 1}, *ints)
+	require.Equal(t, map[string]int64{"a": -1}, int64s)
+	require.Equal(t, map[string]uint{"a": 2}, uints)
+	require.Equal(t, map[string]uint64{"a": 1}, *uint64s)
+	require.Equal(t, map[string]float64{"pi": 3.14}, *floats)
+	require.ErrorContains(t, fs.Set("uint64s", "a:-1"), `strconv.ParseUint: parsing "-1": invalid syntax`)
+}
+
+func TestRegisterer_mapsPrecedence(t *testing.T) {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	r := &values.EnvRegisterer{FlagSet: fs, Prefix: "P_", LookupEnv: values.Dotenv{"P_LABEL": "env=staging"}.LookupEnv}
+	reg := values.RegistererFunc(r.Register).Tracked()
+	labels := reg.StringMapList("label", nil, "=", values.DuplicateKeysError, "labels")
+	require.NoError(t, r.Err())
+	require.NoError(t, values.LoadINI(fs, strings.NewReader("label = tier=web\nlabel = zone=a"), "app.ini"))
+	require.Equal(t, map[string]string{"env": "staging"}, *labels, "env takes precedence over the file")
+
+	require.NoError(t, fs.Parse([]string{"-label", "env=prod", "-label", "tier=db"}))
+	require.Equal(t, map[string]string{"env": "prod", "tier": "db"}, *labels)
+	origin, _ := values.OriginOf(fs.Lookup("label").Value)
+	require.Equal(t, values.Origin{Source: values.SourceCommandLine}, origin)
+
+	require.EqualError(t, fs.Set("label", "tier=api"), `duplicate key "tier"`)
+
+	fs = flag.NewFlagSet("", flag.ContinueOnError)
+	values.FlagSetRegisterer(fs).StringMapList("label", nil, "=", values.DuplicateKeysError, "labels")
+	err := values.LoadINI(fs, strings.NewReader("label = env=dev\nlabel = env=prod"), "app.ini")
+	require.ErrorContains(t, err, `duplicate key "env"`, "duplicates from the same source are errors")
+}
@@ -0,0 +1,227 @@
+package values
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Source identifies where the value of a flag comes from.
+type Source int
+
+// Sources of flag values, from the lowest to the highest precedence.
+const (
+	SourceDefault     Source = iota // the value was not set
+	SourceEnv                       // the value was set from an environment variable
+	SourceFile                      // the value was set from a configuration file
+	SourceCommandLine               // the value was set on the command line, or by any other mean
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceDefault:
+		return "default"
+	case SourceEnv:
+		return "env"
+	case SourceFile:
+		return "file"
+	case SourceCommandLine:
+		return "command line"
+	default:
+		return fmt.Sprintf("Source(%d)", int(s))
+	}
+}
+
+// Origin describes where the value of a flag comes from.
+type Origin struct {
+	Source Source
+	// Name of the environment variable or path of the configuration file the value comes from.
+	Key string
+	// Name of the dotenv file the environment variable was read from, if any, see [EnvRegisterer.Dotenv].
+	Dotenv string
+}
+
+func (o Origin) String() string {
+	switch o.Source {
+	case SourceEnv:
+		if o.Dotenv != "" {
+			return "env $" + o.Key + " (dotenv " + o.Dotenv + ")"
+		}
+		return "env $" + o.Key
+	case SourceFile:
+		return "file " + o.Key
+	default:
+		return o.Source.String()
+	}
+}
+
+// originSetter is implemented by [flag.Value] caring about the [Origin] of their value.
+// The origin is given before the next call to Set, which otherwise comes from [SourceCommandLine].
+type originSetter interface {
+	setOrigin(o Origin)
+}
+
+// SourceSetter is implemented by [flag.Value] whose handling of their input depends on its [Source],
+// such as map values reporting keys set twice from the same source only, see [DuplicateKeys].
+// SetSource is given the source of the next call to Set, which otherwise comes from [SourceCommandLine].
+type SourceSetter interface {
+	SetSource(s Source)
+}
+
+// setFrom sets the named flag of fs, recording the origin of the value.
+func setFrom(fs *flag.FlagSet, name, value string, origin Origin) error {
+	if f := fs.Lookup(name); f != nil {
+		if v, ok := f.Value.(originSetter); ok {
+			v.setOrigin(origin)
+		}
+		if v, ok := f.Value.(SourceSetter); ok {
+			v.SetSource(origin.Source)
+		}
+	}
+	return fs.Set(name, value)
+}
+
+// originGetter is implemented by [flag.Value] recording the [Origin] of their value.
+type originGetter interface {
+	getOrigin() (Origin, bool)
+}
+
+// wrapper implements [flag.Value] forwarding to another one, along with its optional methods.
+// It is embedded by values decorating others, such as [Track] and [Secret].
+type wrapper struct {
+	flag.Value
+}
+
+// Unwrap returns the decorated [flag.Value].
+func (v wrapper) Unwrap() flag.Value { return v.Value }
+
+func (v wrapper) String() string {
+	if v.Value == nil {
+		return ""
+	}
+	return v.Value.String()
+}
+
+func (v wrapper) Get() any {
+	if g, ok := v.Value.(flag.Getter); ok {
+		return g.Get()
+	}
+	return nil
+}
+
+func (v wrapper) IsBoolFlag() bool {
+	b, ok := v.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func (v wrapper) Complete(prefix string) []string {
+	if c, ok := v.Value.(interface{ Complete(prefix string) []string }); ok {
+		return c.Complete(prefix)
+	}
+	return nil
+}
+
+func (v wrapper) Choices() []string {
+	if c, ok := v.Value.(interface{ Choices() []string }); ok {
+		return c.Choices()
+	}
+	return nil
+}
+
+func (v wrapper) envName() string {
+	if n, ok := v.Value.(envNamer); ok {
+		return n.envName()
+	}
+	return ""
+}
+
+func (v wrapper) SetSource(s Source) {
+	if setter, ok := v.Value.(SourceSetter); ok {
+		setter.SetSource(s)
+	}
+}
+
+func (v wrapper) setOrigin(o Origin) {
+	if s, ok := v.Value.(originSetter); ok {
+		s.setOrigin(o)
+	}
+}
+
+func (v wrapper) getOrigin() (Origin, bool) {
+	if g, ok := v.Value.(originGetter); ok {
+		return g.getOrigin()
+	}
+	return Origin{}, false
+}
+
+// tracked implements [flag.Value] wrapping another one and recording the [Origin] of its value.
+type tracked struct {
+	wrapper
+	origin Origin
+	next   *Origin // origin of the next set, if not the command line
+}
+
+func (v *tracked) Set(s string) error {
+	origin := Origin{Source: SourceCommandLine}
+	if v.next != nil {
+		origin, v.next = *v.next, nil
+	}
+	err := v.Value.Set(s)
+	if err == nil {
+		v.origin = origin
+	}
+	return err
+}
+
+func (v *tracked) setOrigin(o Origin) {
+	v.next = &o
+	v.wrapper.setOrigin(o)
+}
+
+func (v *tracked) getOrigin() (Origin, bool) { return v.origin, true }
+
+// Track returns a [flag.Value] wrapping v and recording the [Origin] of its value, see [OriginOf].
+//
+// Values set by [FlagSetEnvRegisterer] are recorded as coming from [SourceEnv].
+// Any other set, such as on the command line, is recorded as coming from [SourceCommandLine].
+func Track(v flag.Value) flag.Value {
+	return &tracked{wrapper{v}, Origin{Source: SourceDefault}, nil}
+}
+
+// OriginOf returns the [Origin] of the value of v.
+// It reports false if v was not wrapped by [Track].
+func OriginOf(v flag.Value) (Origin, bool) {
+	if g, ok := v.(originGetter); ok {
+		return g.getOrigin()
+	}
+	return Origin{}, false
+}
+
+// Tracked returns a [RegistererFunc] wrapping the values it registers with [Track].
+func (f RegistererFunc) Tracked() RegistererFunc {
+	return func(value flag.Value, name, usage string) {
+		f(Track(value), name, usage)
+	}
+}
+
+// Dump writes to w the effective configuration of fs, as a table of the flags
+// with their values and origins. The origin of flags whose values are not wrapped
+// by [Track] is unknown, unless they were not set at all.
+func Dump(w io.Writer, fs *flag.FlagSet) error {
+	placed := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { placed[f.Name] = true })
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint: mnd // padding
+	fmt.Fprintln(tw, "FLAG\tVALUE\tORIGIN")
+	fs.VisitAll(func(f *flag.Flag) {
+		origin := "unknown"
+		if o, ok := OriginOf(f.Value); ok {
+			origin = o.String()
+		} else if !placed[f.Name] {
+			origin = SourceDefault.String()
+		}
+		fmt.Fprintf(tw, "-%s\t%s\t%s\n", f.Name, f.Value, origin)
+	})
+	return tw.Flush()
+}
@@ -0,0 +1,110 @@
+package values_test
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/values"
+)
+
+func ExampleDump() {
+	os.Setenv("FOO_COUNT", "12")
+	defer os.Unsetenv("FOO_COUNT")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	reg := values.FlagSetEnvRegisterer(fs, "FOO_").Tracked()
+	reg.Int("count", 10, "number of items")
+	reg.String("name", "foo", "name of the item")
+	reg.Bool("verbose", false, "enable verbose output")
+	fs.Parse([]string{"-name", "bar"})
+
+	values.Dump(os.Stdout, fs)
+
+	// Output:
+	// FLAG      VALUE  ORIGIN
+	// -count    12     env $FOO_COUNT
+	// -name     bar    command line
+	// -verbose  false  default
+}
+
+func TestTrack(t *testing.T) {
+	t.Setenv("FOO_ENV", "42")
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	reg := values.FlagSetEnvRegisterer(fs, "FOO_").Tracked()
+	reg.Int("env", 12, "an int")
+	reg.Int("cli", 12, "an int")
+	reg.Int("def", 12, "an int")
+	reg.Bool("bool", false, "a bool")
+	fs.Int("untracked", 12, "an int")
+	require.NoError(t, fs.Parse([]string{"-cli", "1", "-bool=true", "-untracked", "1"}))
+
+	testCases := []struct {
+		name   string
+		origin values.Origin
+		ok     bool
+	}{
+		{"env", values.Origin{Source: values.SourceEnv, Key: "FOO_ENV"}, true},
+		{"cli", values.Origin{Source: values.SourceCommandLine}, true},
+		{"def", values.Origin{Source: values.SourceDefault}, true},
+		{"bool", values.Origin{Source: values.SourceCommandLine}, true},
+		{"untracked", values.Origin{}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			origin, ok := values.OriginOf(fs.Lookup(tc.name).Value)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.origin, origin)
+		})
+	}
+
+	t.Run("getter", func(t *testing.T) {
+		require.Equal(t, 42, fs.Lookup("env").Value.(flag.Getter).Get())
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		b := strings.Builder{}
+		fs.SetOutput(&b)
+		fs.PrintDefaults()
+		require.Contains(t, b.String(), "(env $FOO_BOOL)")
+		require.Contains(t, b.String(), "(default 12)")
+	})
+
+	t.Run("dump", func(t *testing.T) {
+		b := strings.Builder{}
+		require.NoError(t, values.Dump(&b, fs))
+		require.Contains(t, b.String(), "-untracked  1      unknown\n")
+	})
+}
+
+// sourceValue is a string value recording the sources given by SetSource.
+type sourceValue struct {
+	value   string
+	sources []values.Source
+}
+
+func (v *sourceValue) String() string                 { return v.value }
+func (v *sourceValue) Set(s string) error             { v.value = s; return nil }
+func (v *sourceValue) SetSource(source values.Source) { v.sources = append(v.sources, source) }
+
+func TestSourceSetter(t *testing.T) {
+	t.Setenv("FOO_VALUE", "env")
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	env, file := &sourceValue{}, &sourceValue{}
+	reg := values.FlagSetEnvRegisterer(fs, "FOO_").Tracked()
+	reg(env, "value", "a value")
+	reg(file, "file", "a value")
+	require.NoError(t, values.LoadINI(fs, strings.NewReader("file = file"), "app.ini"))
+	require.NoError(t, fs.Parse([]string{"-value", "cli"}))
+
+	require.Equal(t, "cli", env.value)
+	require.Equal(t, []values.Source{values.SourceEnv}, env.sources, "command line sets are not announced")
+	require.Equal(t, "file", file.value)
+	require.Equal(t, []values.Source{values.SourceFile}, file.sources)
+}
@@ -2,12 +2,9 @@ package values
 
 import (
 	"flag"
-	"fmt"
 	"net/mail"
 	"net/netip"
 	"net/url"
-	"os"
-	"strings"
 	"time"
 )
 
@@ -21,22 +18,15 @@ func FlagSetRegisterer(fs *flag.FlagSet) RegistererFunc { return fs.Var }
 // FlagSetEnvRegisterer returns a [RegistererFunc] that registers named flags in a [flag.FlagSet]
 // and sets the [flag.Value] with the matching environment variable, ajusting usage accordingly.
 //
-// The environment variable name is derived from the flag name by:
-//
-//   - replacing dashes and dots with underscores
-//   - transforming to upper case
-//   - prepending with given prefix
-//
-// The environment variable is ignored if it fails to set the flag value.
+// See [EnvRegisterer] for details, the environment variable is ignored if it fails to set the flag value.
 func FlagSetEnvRegisterer(fs *flag.FlagSet, prefix string) RegistererFunc {
-	replacer := strings.NewReplacer("-", "_", ".", "_")
-	return func(value flag.Value, name, usage string) {
-		envname := prefix + strings.ToUpper(replacer.Replace(name))
-		fs.Var(value, name, fmt.Sprintf("%s (env $%s)", usage, envname))
-		if val, ok := os.LookupEnv(envname); ok {
-			value.Set(val) //nolint: errcheck,gosec // ignore environment then
-		}
-	}
+	return (&EnvRegisterer{FlagSet: fs, Prefix: prefix}).Register
+}
+
+// FlagSetEnvFileRegisterer is like [FlagSetEnvRegisterer] but also reads flag values from the files
+// named by the environment variables suffixed with fileSuffix (e.g. "_FILE"), see [EnvRegisterer.FileSuffix].
+func FlagSetEnvFileRegisterer(fs *flag.FlagSet, prefix, fileSuffix string) RegistererFunc {
+	return (&EnvRegisterer{FlagSet: fs, Prefix: prefix, FileSuffix: fileSuffix}).Register
 }
 
 // Bool defines a bool flag with specified name, default value, and usage string.
@@ -7,6 +7,7 @@ import (
 	"net/netip"
 	"net/url"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -1514,3 +1515,32 @@ func TestRegisterer_valuesVar(t *testing.T) {
 		})
 	}
 }
+
+func TestFlagSetEnvRegisterer(t *testing.T) {
+	t.Setenv("FOO_INT", "42")
+	t.Setenv("FOO_BAD", "notint")
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	reg := values.FlagSetEnvRegisterer(fs, "FOO_")
+	i := reg.Int("int", 12, "an int")
+	reg.Int("bad", 12, "an int")
+	reg.Int("unset", 12, "an int")
+
+	placed := []string{}
+	fs.Visit(func(f *flag.Flag) { placed = append(placed, f.Name) })
+	require.Equal(t, []string{"int"}, placed)
+	require.Equal(t, 42, *i)
+	require.Equal(t, "12", fs.Lookup("int").DefValue)
+}
+
+func TestFlagSetEnvFileRegisterer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "int")
+	require.NoError(t, os.WriteFile(path, []byte("42\n"), 0o600))
+	t.Setenv("FOO_INT_FILE", path)
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	i := values.FlagSetEnvFileRegisterer(fs, "FOO_", "_FILE").Int("int", 12, "an int")
+
+	require.Equal(t, 42, *i)
+	require.Equal(t, "an int (env $FOO_INT, $FOO_INT_FILE)", fs.Lookup("int").Usage)
+}
@@ -0,0 +1,44 @@
+package values
+
+import "flag"
+
+// secretMask replaces the string representation of secret values.
+const secretMask = "********"
+
+// secret implements [flag.Value] wrapping another one and masking its string representation.
+type secret struct {
+	wrapper
+}
+
+func (v *secret) String() string {
+	if v.wrapper.String() == "" {
+		return ""
+	}
+	return secretMask
+}
+
+// Secret returns a [flag.Value] wrapping v and masking its string representation,
+// so that the value is hidden from usage messages, such as [flag.PrintDefaults],
+// and from configuration dumps, such as [Dump]. Empty values are represented
+// by an empty string, others by a fixed mask.
+//
+// The actual value is still returned by [flag.Getter.Get].
+func Secret(v flag.Value) flag.Value {
+	return &secret{wrapper{v}}
+}
+
+// Secret defines a secret string flag with specified name, default value, and usage string.
+// The value is masked in usage messages, see [Secret].
+// The return value is the address of a string variable that stores the value of the flag.
+func (f RegistererFunc) Secret(name string, value string, usage string) *string {
+	f(Secret(BasicVar(&value)), name, usage)
+	return &value
+}
+
+// SecretVar defines a secret string flag with specified name, default value, and usage string.
+// The value is masked in usage messages, see [Secret].
+// The argument p points to a string variable in which to store the value of the flag.
+func (f RegistererFunc) SecretVar(p *string, name string, value string, usage string) {
+	*p = value
+	f(Secret(BasicVar(p)), name, usage)
+}
@@ -0,0 +1,62 @@
+package values_test
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rlibaert/flag/values"
+)
+
+func ExampleSecret() {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	reg := values.FlagSetRegisterer(fs)
+	reg.Secret("token", "s3cr3t", "API token")
+	reg.Secret("password", "", "password")
+	fs.PrintDefaults()
+
+	// Output:
+	//   -password value
+	//     	password
+	//   -token value
+	//     	API token (default ********)
+}
+
+func TestSecret(t *testing.T) {
+	t.Setenv("FOO_TOKEN", "s3cr3t")
+
+	var password string
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	reg := values.FlagSetEnvRegisterer(fs, "FOO_").Tracked()
+	token := reg.Secret("token", "", "a secret")
+	reg.SecretVar(&password, "password", "default", "a secret")
+	fs.Var(values.Secret(values.Track(values.Basic[int]())), "pin", "a secret")
+	require.NoError(t, fs.Parse([]string{"-pin", "1234"}))
+
+	require.Equal(t, "s3cr3t", *token)
+	require.Equal(t, "default", password)
+	require.Equal(t, "s3cr3t", fs.Lookup("token").Value.(flag.Getter).Get())
+	require.Equal(t, 1234, fs.Lookup("pin").Value.(flag.Getter).Get())
+	require.Equal(t, "********", fs.Lookup("token").Value.String())
+	require.Equal(t, "********", fs.Lookup("password").DefValue)
+
+	origin, ok := values.OriginOf(fs.Lookup("pin").Value)
+	require.True(t, ok)
+	require.Equal(t, values.Origin{Source: values.SourceCommandLine}, origin)
+
+	b := strings.Builder{}
+	require.NoError(t, values.Dump(&b, fs))
+	require.Equal(t, `FLAG       VALUE     ORIGIN
+-password  ********  default
+-pin       ********  command line
+-token     ********  env $FOO_TOKEN
+`, b.String())
+
+	t.Run("empty", func(t *testing.T) {
+		require.Empty(t, values.Secret(values.Basic[string]()).String())
+	})
+}
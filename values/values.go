@@ -1,9 +1,9 @@
 // Package values provides implementations of [flag.Value] and primitives to register them.
 //
-// Aside of [RegistererFunc], there is 30 functions declaring various [flag.Value].
+// Aside of [RegistererFunc], there is 42 functions declaring various [flag.Value].
 // Their names are matched by this regular expression:
 //
-//	(Generic|Basic|Stringer|Time|Duration)(List|Slice)?(Var)?
+//	(Generic|Basic|Stringer|Time|Duration|Enum|ByteSize)(List|Slice)?(Var)?
 //
 // If neither 'List' nor 'Slice' are present, then the value is parsed and
 // stored to a variable. Multiple sets will overwrite the value.
@@ -14,6 +14,10 @@
 // at once every time the flag is invoked. The [flag.Value] will split the input
 // string and parse the substrings.
 //
+// Map values, declared by (Generic|Basic)Map(List)?(Var)?, follow the same
+// rules: 'List' sets a single key=value entry at a time while otherwise all
+// entries are set at once.
+//
 // If 'Var' is present, the function accepts another pointer parameter which
 // will be used to store the parsed values.
 //
@@ -24,8 +28,24 @@
 //   - 'Stringer' for object imlementing [fmt.Stringer] ([*url.URL], [netip.Addr], ...)
 //   - 'Time' takes a layout for use in [time.Time.Format] and [time.Parse]
 //   - 'Duration' for [time.Duration] values
+//   - 'ByteSize' for byte counts with SI or IEC units (10KB, 1.5GiB, ...)
+//   - 'Enum' accepts only a fixed set of choices, which it completes
+//
+// [Counter] and [CounterVar] declare boolean-like values counting the
+// occurrences of their flag, such as -v -v -v.
+//
+// The values shall then be registered using [flag.FlagSet.Var], or all at once
+// from the fields of a tagged struct with [RegistererFunc.Bind].
+//
+// Environment variables may be read from dotenv files with [ReadDotenv] and
+// mapped to flags by [EnvRegisterer].
+//
+// Configuration files may be applied to a [flag.FlagSet] with [LoadConfigFile],
+// [LoadJSON] or [LoadINI].
 //
-// The values shall then be registered using [flag.FlagSet.Var].
+// Any [flag.Value] may be wrapped by [Track] to record where its value comes
+// from, see [OriginOf] and [Dump], and by [Secret] to hide its value from usage
+// messages and dumps.
 package values
 
 import (